@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"meeting-scheduler/internal/model"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiClient is the backend that goes through a server's HTTP API, acting as
+// the owner of the API key.
+type apiClient struct {
+	base   string
+	key    string
+	client *http.Client
+}
+
+// apiPrefix is the path of the API version the client speaks.
+const apiPrefix = "/api/v1"
+
+func newAPIClient(server, key string) *apiClient {
+	return &apiClient{base: strings.TrimRight(server, "/") + apiPrefix, key: key, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// apiError is a response with a status of 400 or above.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.message, e.status)
+}
+
+// do sends body as JSON and decodes the response into out unless it is nil.
+func (c *apiClient) do(ctx context.Context, method, path string, body, out any) error {
+	var payload io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, payload)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.key != "" {
+		req.Header.Set("Authorization", "Bearer "+c.key)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&e) != nil || e.Error == "" {
+			e.Error = http.StatusText(resp.StatusCode)
+		}
+		return &apiError{status: resp.StatusCode, message: e.Error}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+func (c *apiClient) AddUser(ctx context.Context, u *model.User) (*model.Registration, error) {
+	var registration model.Registration
+	if err := c.do(ctx, http.MethodPost, "/users", u, &registration); err != nil {
+		return nil, err
+	}
+	return &registration, nil
+}
+
+func (c *apiClient) ListUsers(ctx context.Context) ([]*model.User, error) {
+	var users []*model.User
+	return users, c.do(ctx, http.MethodGet, "/users", nil, &users)
+}
+
+func (c *apiClient) CreateEvent(ctx context.Context, e *model.Event) error {
+	return c.do(ctx, http.MethodPost, "/events", e, e)
+}
+
+func (c *apiClient) GetEvent(ctx context.Context, id string) (*model.Event, error) {
+	var e model.Event
+	if err := c.do(ctx, http.MethodGet, "/events/"+url.PathEscape(id), nil, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (c *apiClient) ListEvents(ctx context.Context) ([]*model.Event, error) {
+	var events []*model.Event
+	return events, c.do(ctx, http.MethodGet, "/events", nil, &events)
+}
+
+func (c *apiClient) DeleteEvent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/events/"+url.PathEscape(id), nil, nil)
+}
+
+// SetAvailability creates or replaces the key owner's availability.
+func (c *apiClient) SetAvailability(ctx context.Context, av *model.Availability) error {
+	userID, err := c.me(ctx)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPut, availabilityPath(av.EventID, userID), av, av)
+}
+
+func (c *apiClient) GetAvailability(ctx context.Context, eventID, userID string) (model.Availability, error) {
+	var av model.Availability
+	if userID == "" {
+		var err error
+		if userID, err = c.me(ctx); err != nil {
+			return av, err
+		}
+	}
+	return av, c.do(ctx, http.MethodGet, availabilityPath(eventID, userID), nil, &av)
+}
+
+// me returns the ID of the key owner.
+func (c *apiClient) me(ctx context.Context) (string, error) {
+	var me model.User
+	if err := c.do(ctx, http.MethodGet, "/me", nil, &me); err != nil {
+		return "", err
+	}
+	return me.ID, nil
+}
+
+func availabilityPath(eventID, userID string) string {
+	return "/events/" + url.PathEscape(eventID) + "/availability/" + url.PathEscape(userID)
+}
+
+func (c *apiClient) Suggest(ctx context.Context, eventID string) (*model.SuggestionResult, error) {
+	var result model.SuggestionResult
+	if err := c.do(ctx, http.MethodGet, "/events/"+url.PathEscape(eventID)+"/suggestions", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *apiClient) Export(ctx context.Context) (*model.Archive, error) {
+	var a model.Archive
+	if err := c.do(ctx, http.MethodGet, "/admin/export", nil, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (c *apiClient) Import(ctx context.Context, a *model.Archive, mode string, dryRun bool) (*model.ImportReport, error) {
+	query := url.Values{"mode": {mode}, "dry_run": {strconv.FormatBool(dryRun)}}
+	var report model.ImportReport
+	if err := c.do(ctx, http.MethodPost, "/admin/import?"+query.Encode(), a, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (c *apiClient) Close(context.Context) error { return nil }
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"meeting-scheduler/internal/archive"
+	"meeting-scheduler/internal/model"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// backend carries out commands, either over the HTTP API or directly on a
+// data file.
+type backend interface {
+	AddUser(ctx context.Context, u *model.User) (*model.Registration, error)
+	ListUsers(ctx context.Context) ([]*model.User, error)
+	CreateEvent(ctx context.Context, e *model.Event) error
+	GetEvent(ctx context.Context, id string) (*model.Event, error)
+	ListEvents(ctx context.Context) ([]*model.Event, error)
+	DeleteEvent(ctx context.Context, id string) error
+	// SetAvailability creates or replaces the acting user's availability.
+	SetAvailability(ctx context.Context, av *model.Availability) error
+	// GetAvailability returns the availability of userID, or of the acting
+	// user when userID is empty.
+	GetAvailability(ctx context.Context, eventID, userID string) (model.Availability, error)
+	Suggest(ctx context.Context, eventID string) (*model.SuggestionResult, error)
+	Export(ctx context.Context) (*model.Archive, error)
+	Import(ctx context.Context, a *model.Archive, mode string, dryRun bool) (*model.ImportReport, error)
+	Close(ctx context.Context) error
+}
+
+type cli struct {
+	backend
+	cmd    command
+	out    *printer
+	stdin  io.Reader
+	stderr io.Writer
+}
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	// createsData lets the command run on a data file that does not exist
+	// yet; other commands refuse, so a mistyped path is not taken for an
+	// empty store.
+	createsData bool
+	run         func(ctx context.Context, c *cli, args []string) error
+}
+
+var commands = []command{
+	{name: "user add", args: "-id ID [-name NAME]", summary: "register a user and print their first API key", run: userAdd, createsData: true},
+	{name: "user list", summary: "list registered users", run: userList},
+	{name: "event create", args: "[-f FILE] [-id ID] [-title TITLE] [-duration MIN] [-participants IDS] [-co-organizers IDS] [-slot START/END]...", summary: "create an event", run: eventCreate},
+	{name: "event show", args: "EVENT", summary: "show an event", run: eventShow},
+	{name: "event list", summary: "list events", run: eventList},
+	{name: "event delete", args: "EVENT", summary: "move an event to the trash", run: eventDelete},
+	{name: "availability set", args: "EVENT [-slot START/END]... [-decline]", summary: "set your availability for an event", run: availabilitySet},
+	{name: "availability show", args: "EVENT [USER]", summary: "show availability for an event", run: availabilityShow},
+	{name: "suggest", args: "EVENT", summary: "suggest time slots for an event", run: suggest},
+	{name: "export", args: "[-f FILE]", summary: "write all users, events and availability as an archive", run: export},
+	{name: "import", args: "[-f FILE] [-mode merge|replace] [-dry-run]", summary: "load an archive made by export", run: importData, createsData: true},
+}
+
+// newFlags returns the flag set of the running command.
+func (c *cli) newFlags() *flag.FlagSet {
+	fs := flag.NewFlagSet(c.cmd.name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: schedctl %s %s\n\n%s.\n", c.cmd.name, c.cmd.args, c.cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseArgs parses flags that may come before, after or between the
+// positional arguments and checks that there are between min and max of the
+// latter.
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) < min || len(positional) > max {
+		fs.Usage()
+		return nil, flag.ErrHelp
+	}
+	return positional, nil
+}
+
+func userAdd(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlags()
+	var u model.User
+	fs.StringVar(&u.ID, "id", "", "user ID")
+	fs.StringVar(&u.Name, "name", "", "display name")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if u.ID == "" {
+		return errors.New("-id is required")
+	}
+	registration, err := c.AddUser(ctx, &u)
+	if err != nil {
+		return err
+	}
+	return c.out.print(registration, []string{"ID", "NAME", "API KEY"},
+		[][]string{{registration.User.ID, registration.User.Name, registration.Credential.Key}})
+}
+
+func userList(ctx context.Context, c *cli, args []string) error {
+	if _, err := parseArgs(c.newFlags(), args, 0, 0); err != nil {
+		return err
+	}
+	users, err := c.ListUsers(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	rows := make([][]string, len(users))
+	for i, u := range users {
+		rows[i] = []string{u.ID, u.Name}
+	}
+	return c.out.print(users, []string{"ID", "NAME"}, rows)
+}
+
+func eventCreate(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlags()
+	var (
+		e            model.Event
+		file         string
+		participants list
+		coOrganizers list
+		slots        slotList
+	)
+	fs.StringVar(&file, "f", "", "JSON file with the event; other flags override its fields")
+	fs.StringVar(&e.ID, "id", "", "event ID")
+	fs.StringVar(&e.Title, "title", "", "title")
+	fs.IntVar(&e.DurationMin, "duration", 0, "length of the meeting in minutes")
+	fs.Var(&participants, "participants", "comma separated user IDs of the participants")
+	fs.Var(&coOrganizers, "co-organizers", "comma separated user IDs allowed to edit the event")
+	fs.Var(&slots, "slot", "time range the event may take place in, as START/END in RFC 3339; repeatable")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if file != "" {
+		fromFile := model.Event{}
+		if err := readJSON(file, c.stdin, &fromFile); err != nil {
+			return err
+		}
+		// Apply only the flags that were given on top of the file.
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "id":
+				fromFile.ID = e.ID
+			case "title":
+				fromFile.Title = e.Title
+			case "duration":
+				fromFile.DurationMin = e.DurationMin
+			}
+		})
+		e = fromFile
+	}
+	if participants != nil {
+		e.Participants = participants
+	}
+	if coOrganizers != nil {
+		e.CoOrganizers = coOrganizers
+	}
+	if slots != nil {
+		e.Slots = slots
+	}
+	if e.ID == "" {
+		return errors.New("an event ID is required")
+	}
+	if err := c.CreateEvent(ctx, &e); err != nil {
+		return err
+	}
+	return c.printEvents(&e, &e)
+}
+
+func eventShow(ctx context.Context, c *cli, args []string) error {
+	pos, err := parseArgs(c.newFlags(), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	e, err := c.GetEvent(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return c.printEvents(e, e)
+}
+
+func eventList(ctx context.Context, c *cli, args []string) error {
+	if _, err := parseArgs(c.newFlags(), args, 0, 0); err != nil {
+		return err
+	}
+	events, err := c.ListEvents(ctx)
+	if err != nil {
+		return err
+	}
+	return c.printEvents(events, events...)
+}
+
+func (c *cli) printEvents(v any, events ...*model.Event) error {
+	rows := make([][]string, len(events))
+	for i, e := range events {
+		rows[i] = []string{e.ID, e.Title, strconv.Itoa(e.DurationMin), e.Organizer,
+			strings.Join(e.Participants, ","), formatSlots(e.Slots), formatSlots(e.FinalSessions)}
+	}
+	return c.out.print(v, []string{"ID", "TITLE", "MINUTES", "ORGANIZER", "PARTICIPANTS", "SLOTS", "FINAL"}, rows)
+}
+
+func eventDelete(ctx context.Context, c *cli, args []string) error {
+	pos, err := parseArgs(c.newFlags(), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if err := c.DeleteEvent(ctx, pos[0]); err != nil {
+		return err
+	}
+	return c.out.print(map[string]string{"id": pos[0], "status": "deleted"},
+		[]string{"ID", "STATUS"}, [][]string{{pos[0], "deleted"}})
+}
+
+func availabilitySet(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlags()
+	var (
+		slots   slotList
+		decline bool
+	)
+	fs.Var(&slots, "slot", "time range you are available in, as START/END in RFC 3339; repeatable")
+	fs.BoolVar(&decline, "decline", false, "record that you will not attend")
+	pos, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if decline == (len(slots) > 0) {
+		return errors.New("give either -slot or -decline")
+	}
+	av := model.Availability{EventID: pos[0], Slots: slots, Declined: decline}
+	if err := c.SetAvailability(ctx, &av); err != nil {
+		return err
+	}
+	return c.printAvailability(av, av)
+}
+
+func availabilityShow(ctx context.Context, c *cli, args []string) error {
+	pos, err := parseArgs(c.newFlags(), args, 1, 2)
+	if err != nil {
+		return err
+	}
+	userID := ""
+	if len(pos) == 2 {
+		userID = pos[1]
+	}
+	av, err := c.GetAvailability(ctx, pos[0], userID)
+	if err != nil {
+		return err
+	}
+	return c.printAvailability(av, av)
+}
+
+func (c *cli) printAvailability(v any, list ...model.Availability) error {
+	rows := make([][]string, len(list))
+	for i, av := range list {
+		rows[i] = []string{av.EventID, av.UserID, strconv.FormatBool(av.Declined), formatSlots(av.Slots), av.UpdatedAt.Format(time.RFC3339)}
+	}
+	return c.out.print(v, []string{"EVENT", "USER", "DECLINED", "SLOTS", "UPDATED"}, rows)
+}
+
+func suggest(ctx context.Context, c *cli, args []string) error {
+	pos, err := parseArgs(c.newFlags(), args, 1, 1)
+	if err != nil {
+		return err
+	}
+	result, err := c.Suggest(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	if c.out.format == formatTable && !result.Viable {
+		fmt.Fprintf(c.out.w, "No slot reaches the required attendance of %d: %s\n\n", result.RequiredAttendance, result.Reason)
+	}
+	rows := make([][]string, len(result.SuggestedSlots))
+	for i, s := range result.SuggestedSlots {
+		rows[i] = []string{s.Slot.Start.Format(time.RFC3339), s.Slot.End.Format(time.RFC3339),
+			strings.Join(s.UnavailableUsers, ","), strings.Join(s.DeclinedUsers, ",")}
+	}
+	return c.out.print(result, []string{"START", "END", "UNAVAILABLE", "DECLINED"}, rows)
+}
+
+func export(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlags()
+	file := fs.String("f", "-", "file to write, or - for standard output")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	a, err := c.Export(ctx)
+	if err != nil {
+		return err
+	}
+	if *file == "-" {
+		return archive.Write(c.out.w, a)
+	}
+	f, err := os.Create(*file)
+	if err != nil {
+		return err
+	}
+	if err := archive.Write(f, a); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func importData(ctx context.Context, c *cli, args []string) error {
+	fs := c.newFlags()
+	file := fs.String("f", "-", "archive to read, or - for standard input")
+	mode := fs.String("mode", model.ImportMerge, "merge keeps existing records that differ from the archive; replace overwrites them and deletes events and availability missing from it")
+	dryRun := fs.Bool("dry-run", false, "only report what would change")
+	if _, err := parseArgs(fs, args, 0, 0); err != nil {
+		return err
+	}
+	r := c.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	a, err := archive.Decode(r)
+	if err != nil {
+		return err
+	}
+	report, err := c.Import(ctx, a, *mode, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if c.out.format == formatTable && report.DryRun {
+		fmt.Fprint(c.out.w, "Dry run, nothing was written.\n\n")
+	}
+	counts := func(entity string, n model.ImportCounts) []string {
+		return []string{entity, strconv.Itoa(n.Created), strconv.Itoa(n.Updated), strconv.Itoa(n.Unchanged), strconv.Itoa(n.Kept), strconv.Itoa(n.Deleted)}
+	}
+	rows := [][]string{
+		counts(model.EntityUser, report.Users),
+		counts(model.EntityEvent, report.Events),
+		counts(model.EntityAvailability, report.Availability),
+		counts(model.EntityCredential, report.Credentials),
+	}
+	if err := c.out.print(report, []string{"ENTITY", "CREATED", "UPDATED", "UNCHANGED", "KEPT", "DELETED"}, rows); err != nil {
+		return err
+	}
+	if c.out.format == formatTable && len(report.Conflicts) > 0 {
+		fmt.Fprintln(c.out.w, "\nConflicts:")
+		for _, conflict := range report.Conflicts {
+			fmt.Fprintf(c.out.w, "  %s %s: %s\n", conflict.Entity, conflict.ID, conflict.Resolution)
+		}
+	}
+	return nil
+}
+
+// readJSON decodes the JSON file at path, or standard input for "-", into v.
+func readJSON(path string, stdin io.Reader, v any) error {
+	r := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nil
+}
+
+// list is a comma separated flag value.
+type list []string
+
+func (l *list) String() string { return strings.Join(*l, ",") }
+
+func (l *list) Set(v string) error {
+	*l = list{}
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// slotList collects repeated START/END flag values.
+type slotList []model.Slot
+
+func (s *slotList) String() string { return formatSlots(*s) }
+
+func (s *slotList) Set(v string) error {
+	start, end, ok := strings.Cut(v, "/")
+	if !ok {
+		return errors.New("expected START/END")
+	}
+	var slot model.Slot
+	var err error
+	if slot.Start, err = time.Parse(time.RFC3339, start); err != nil {
+		return err
+	}
+	if slot.End, err = time.Parse(time.RFC3339, end); err != nil {
+		return err
+	}
+	*s = append(*s, slot)
+	return nil
+}
+
+func formatSlots(slots []model.Slot) string {
+	parts := make([]string, len(slots))
+	for i, s := range slots {
+		parts[i] = s.Start.Format(time.RFC3339) + "/" + s.End.Format(time.RFC3339)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,124 @@
+// Command schedctl manages a meeting scheduler from the command line. It
+// talks to a running server over its HTTP API, or with -data works directly
+// on the data file of the file storage backend while the server is stopped.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// Environment variables that stand in for the global flags. The API key has
+// no flag, as command lines are visible to other users of the host.
+const (
+	envServer = "SCHEDCTL_SERVER"
+	envAPIKey = "SCHEDCTL_API_KEY"
+	envData   = "SCHEDCTL_DATA"
+)
+
+const defaultServer = "http://localhost:8080"
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.LookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "schedctl:", err)
+		os.Exit(1)
+	}
+}
+
+// options are the global flags.
+type options struct {
+	server string
+	apiKey string
+	data   string
+	actor  string
+	output string
+}
+
+// run executes the command in args, which excludes the program name.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, lookupEnv func(string) (string, bool)) error {
+	opts := options{server: defaultServer, output: formatTable}
+	if v, ok := lookupEnv(envServer); ok && v != "" {
+		opts.server = v
+	}
+	opts.apiKey, _ = lookupEnv(envAPIKey)
+	opts.data, _ = lookupEnv(envData)
+
+	fs := flag.NewFlagSet("schedctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.server, "server", opts.server, "base URL of the server API ($"+envServer+")")
+	fs.StringVar(&opts.data, "data", opts.data, "snapshot file of the file storage backend to work on instead of the API ($"+envData+")")
+	fs.StringVar(&opts.actor, "as", "", "user to act as on the data file; over the API the user is the owner of $"+envAPIKey)
+	fs.StringVar(&opts.output, "o", opts.output, "output format: table or json")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if opts.output != formatTable && opts.output != formatJSON {
+		return fmt.Errorf("unknown output format %q, expected table or json", opts.output)
+	}
+
+	cmd, rest, ok := findCommand(fs.Args())
+	if !ok {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(stderr, "unknown command %q\n\n", strings.Join(fs.Args(), " "))
+		}
+		usage(fs)
+		return flag.ErrHelp
+	}
+
+	var b backend
+	if opts.data != "" {
+		sb, err := openStore(opts.data, opts.actor, cmd.createsData)
+		if err != nil {
+			return err
+		}
+		b = sb
+	} else {
+		b = newAPIClient(opts.server, opts.apiKey)
+	}
+	c := &cli{backend: b, cmd: cmd, out: &printer{w: stdout, format: opts.output}, stdin: stdin, stderr: stderr}
+	err := cmd.run(ctx, c, rest)
+	if cerr := b.Close(ctx); cerr != nil {
+		err = errors.Join(err, cerr)
+	}
+	return err
+}
+
+func findCommand(args []string) (command, []string, bool) {
+	for _, n := range []int{2, 1} {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		for _, cmd := range commands {
+			if cmd.name == name {
+				return cmd, args[n:], true
+			}
+		}
+	}
+	return command{}, nil, false
+}
+
+func usage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "Usage: schedctl [flags] <command> [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-20s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nRun a command with -h for its arguments.")
+	fmt.Fprintln(w, "\nFlags:")
+	fs.PrintDefaults()
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"meeting-scheduler/internal/handler"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const slot = "2025-05-20T09:00:00Z/2025-05-20T12:00:00Z"
+
+// schedctl runs the command line with env as the environment and returns
+// what it wrote to standard output.
+func schedctl(t *testing.T, env map[string]string, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	lookup := func(k string) (string, bool) { v, ok := env[k]; return v, ok }
+	err := run(t.Context(), args, strings.NewReader(""), &stdout, &stderr, lookup)
+	return stdout.String(), err
+}
+
+func mustRun(t *testing.T, env map[string]string, args ...string) string {
+	t.Helper()
+	out, err := schedctl(t, env, args...)
+	require.NoError(t, err, strings.Join(args, " "))
+	return out
+}
+
+func TestDataFile(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data.json")
+	env := map[string]string{envData: data}
+
+	out := mustRun(t, env, "user", "add", "-id", "u1", "-name", "Alice")
+	assert.Contains(t, out, "msk_")
+	mustRun(t, env, "user", "add", "-id", "u2", "-name", "Bob")
+	mustRun(t, env, "-as", "u1", "event", "create", "-id", "e1", "-title", "Planning", "-duration", "60", "-participants", "u1,u2", "-slot", slot)
+	mustRun(t, env, "-as", "u2", "availability", "set", "e1", "-slot", "2025-05-20T10:00:00Z/2025-05-20T11:00:00Z")
+
+	_, err := schedctl(t, env, "event", "delete", "e1")
+	assert.EqualError(t, err, "-as is required to change data in the data file")
+
+	var events []model.Event
+	require.NoError(t, json.Unmarshal([]byte(mustRun(t, env, "-o", "json", "event", "list")), &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "u1", events[0].Organizer)
+
+	out = mustRun(t, env, "suggest", "e1")
+	assert.Contains(t, out, "2025-05-20T10:00:00Z  2025-05-20T11:00:00Z  u1")
+
+	backup := filepath.Join(dir, "backup.json")
+	mustRun(t, env, "export", "-f", backup)
+	restored := map[string]string{envData: filepath.Join(dir, "restored.json")}
+	_, err = schedctl(t, restored, "user", "list")
+	assert.ErrorContains(t, err, "does not exist")
+	out = mustRun(t, restored, "import", "-f", backup, "-dry-run")
+	assert.Contains(t, out, "Dry run")
+	_, err = schedctl(t, restored, "user", "list")
+	assert.ErrorContains(t, err, "does not exist", "a dry run writes nothing")
+	out = mustRun(t, restored, "import", "-f", backup)
+	assert.Contains(t, out, "event         1        0        0          0     0")
+	assert.Contains(t, out, "credential    2        0        0          0     0")
+	out = mustRun(t, restored, "availability", "show", "e1", "u2")
+	assert.Contains(t, out, "2025-05-20T10:00:00Z/2025-05-20T11:00:00Z")
+	store, err := repository.OpenMemoryStore(restored[envData], repository.WithJournal(restored[envData]+".journal", 1<<30))
+	require.NoError(t, err)
+	entries, err := store.Audit().Query(t.Context(), model.AuditFilter{Entity: model.EntityArchive})
+	require.NoError(t, err)
+	require.NoError(t, store.Close(t.Context()))
+	require.Len(t, entries, 1, "imports into a data file are audited")
+	assert.Equal(t, model.AuditImport, entries[0].Action)
+	assert.Equal(t, "schedctl", entries[0].Actor)
+
+	mustRun(t, env, "-as", "u1", "event", "delete", "e1")
+	out = mustRun(t, env, "import", "-f", backup)
+	assert.Contains(t, out, "event e1: kept")
+	out = mustRun(t, env, "import", "-f", backup, "-mode", "replace")
+	assert.Contains(t, out, "event e1: replaced")
+	mustRun(t, env, "event", "show", "e1")
+}
+
+func TestDataFile_Locked(t *testing.T) {
+	data := filepath.Join(t.TempDir(), "data.json")
+	env := map[string]string{envData: data}
+	mustRun(t, env, "user", "add", "-id", "u1")
+	snapshot, err := os.ReadFile(data)
+	require.NoError(t, err)
+
+	mustRun(t, env, "user", "list")
+	after, err := os.ReadFile(data)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, after, "commands that only read do not save")
+
+	store, err := repository.OpenMemoryStore(data, repository.WithJournal(data+".journal", 1<<30))
+	require.NoError(t, err)
+	defer store.Close(t.Context())
+	_, err = schedctl(t, env, "user", "list")
+	assert.EqualError(t, err, "data file "+data+" is in use; stop the server before working on it")
+}
+
+func TestAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	svc := service.NewSchedulerService(
+		repository.NewInMemoryUserRepository(),
+		repository.NewInMemoryEventRepository(),
+		repository.NewInMemoryAvailabilityRepository(),
+		service.WithAdmins("u1"),
+	)
+	var deprecated []string
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		if c.Writer.Header().Get("Deprecation") != "" {
+			deprecated = append(deprecated, c.Request.Method+" "+c.FullPath())
+		}
+	})
+	handler.NewHandler(svc).RegisterRoutes(r)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+	defer func() { assert.Empty(t, deprecated, "schedctl uses deprecated routes") }()
+	env := map[string]string{envServer: srv.URL}
+
+	var registration model.Registration
+	require.NoError(t, json.Unmarshal([]byte(mustRun(t, env, "-o", "json", "user", "add", "-id", "u1")), &registration))
+	env[envAPIKey] = registration.Credential.Key
+
+	mustRun(t, env, "event", "create", "-id", "e1", "-duration", "60", "-participants", "u1", "-slot", slot)
+	out := mustRun(t, env, "availability", "set", "e1", "-slot", slot)
+	assert.Contains(t, out, "e1     u1    false")
+	mustRun(t, env, "availability", "set", "e1", "-decline")
+	out = mustRun(t, env, "availability", "show", "e1")
+	assert.Contains(t, out, "e1     u1    true")
+
+	out = mustRun(t, env, "event", "list")
+	assert.Contains(t, out, "e1")
+	backup := filepath.Join(t.TempDir(), "backup.json")
+	mustRun(t, env, "export", "-f", backup)
+	mustRun(t, env, "event", "delete", "e1")
+	_, err := schedctl(t, env, "event", "show", "e1")
+	assert.ErrorContains(t, err, "(HTTP 404)")
+
+	var report model.ImportReport
+	require.NoError(t, json.Unmarshal([]byte(mustRun(t, env, "-o", "json", "import", "-f", backup, "-mode", "replace")), &report))
+	assert.Equal(t, model.ImportCounts{Updated: 1}, report.Events)
+	mustRun(t, env, "event", "show", "e1")
+
+	delete(env, envAPIKey)
+	_, err = schedctl(t, env, "user", "list")
+	assert.ErrorContains(t, err, "(HTTP 401)")
+}
+
+func TestUsage(t *testing.T) {
+	_, err := schedctl(t, nil)
+	assert.ErrorIs(t, err, flag.ErrHelp)
+	_, err = schedctl(t, nil, "event", "rename")
+	assert.ErrorIs(t, err, flag.ErrHelp)
+	_, err = schedctl(t, nil, "-o", "yaml", "user", "list")
+	assert.EqualError(t, err, `unknown output format "yaml", expected table or json`)
+	_, err = schedctl(t, map[string]string{envServer: "http://127.0.0.1:0"}, "event", "show")
+	assert.ErrorIs(t, err, flag.ErrHelp, "a missing argument is reported before anything is sent")
+}
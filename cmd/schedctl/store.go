@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"meeting-scheduler/internal/archive"
+	"meeting-scheduler/internal/config"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"os"
+	"sort"
+	"time"
+)
+
+// offlineActor is recorded as the actor of changes schedctl makes to a data
+// file without -as.
+const offlineActor = "schedctl"
+
+// storeBackend is the backend that works on the data file of the file
+// storage backend, through the same service the server uses. The data file
+// is locked while either has it open, so it refuses to run alongside the
+// server.
+type storeBackend struct {
+	store *repository.MemoryStore
+	svc   *service.SchedulerService
+	actor string
+}
+
+// openStore opens the snapshot at path and its journal, laid out as the
+// server lays them out. Unless create is set the snapshot must exist.
+func openStore(path, actor string, create bool) (*storeBackend, error) {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) && !create {
+		return nil, fmt.Errorf("data file %s does not exist", path)
+	}
+	store, err := repository.OpenMemoryStore(path,
+		repository.WithJournal(path+".journal", config.Default().Storage.JournalCompactSize))
+	if errors.Is(err, repository.ErrStoreLocked) {
+		return nil, fmt.Errorf("data file %s is in use; stop the server before working on it", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	svc := service.NewSchedulerService(store.Users(), store.Events(), store.Availability(),
+		service.WithCredentials(store.Credentials()),
+		service.WithMagicLinks(store.MagicLinks()),
+		service.WithAuditLog(store.Audit()),
+		service.WithVersions(store.Versions()),
+	)
+	return &storeBackend{store: store, svc: svc, actor: actor}, nil
+}
+
+// actorID returns the user given with -as, which commands that change an
+// event or availability act as.
+func (b *storeBackend) actorID() (string, error) {
+	if b.actor == "" {
+		return "", errors.New("-as is required to change data in the data file")
+	}
+	return b.actor, nil
+}
+
+func (b *storeBackend) AddUser(ctx context.Context, u *model.User) (*model.Registration, error) {
+	return b.svc.RegisterUser(ctx, u)
+}
+
+func (b *storeBackend) ListUsers(ctx context.Context) ([]*model.User, error) {
+	return b.svc.GetAllUsers(ctx)
+}
+
+func (b *storeBackend) CreateEvent(ctx context.Context, e *model.Event) error {
+	actor, err := b.actorID()
+	if err != nil {
+		return err
+	}
+	return b.svc.CreateEvent(ctx, actor, e)
+}
+
+func (b *storeBackend) GetEvent(ctx context.Context, id string) (*model.Event, error) {
+	return b.svc.GetEvent(ctx, id)
+}
+
+// ListEvents returns every live event, not only those of the -as user.
+func (b *storeBackend) ListEvents(ctx context.Context) ([]*model.Event, error) {
+	events, err := b.store.Events().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+func (b *storeBackend) DeleteEvent(ctx context.Context, id string) error {
+	actor, err := b.actorID()
+	if err != nil {
+		return err
+	}
+	return b.svc.DeleteEvent(ctx, actor, id)
+}
+
+func (b *storeBackend) SetAvailability(ctx context.Context, av *model.Availability) error {
+	actor, err := b.actorID()
+	if err != nil {
+		return err
+	}
+	if _, err := b.store.Availability().Get(ctx, av.EventID, actor); err != nil {
+		return b.svc.AddAvailability(ctx, actor, av)
+	}
+	return b.svc.UpdateAvailability(ctx, actor, av)
+}
+
+func (b *storeBackend) GetAvailability(ctx context.Context, eventID, userID string) (model.Availability, error) {
+	if userID == "" {
+		actor, err := b.actorID()
+		if err != nil {
+			return model.Availability{}, err
+		}
+		userID = actor
+	}
+	return b.svc.GetAvailability(ctx, eventID, userID)
+}
+
+func (b *storeBackend) Suggest(ctx context.Context, eventID string) (*model.SuggestionResult, error) {
+	return b.svc.SuggestSlots(ctx, eventID)
+}
+
+// Export returns an archive of the data file.
+func (b *storeBackend) Export(ctx context.Context) (*model.Archive, error) {
+	return archive.Export(ctx, b.archiveRepositories(), time.Now())
+}
+
+// Import loads a into the data file. Unlike over the API, it needs no
+// admin: whoever can write the data file can change it anyway. The import
+// is audited as made by the -as user, or by offlineActor without one.
+func (b *storeBackend) Import(ctx context.Context, a *model.Archive, mode string, dryRun bool) (*model.ImportReport, error) {
+	actor := b.actor
+	if actor == "" {
+		actor = offlineActor
+	}
+	return b.svc.RestoreArchive(ctx, actor, a, mode, dryRun)
+}
+
+func (b *storeBackend) archiveRepositories() archive.Repositories {
+	s := b.store
+	return archive.Repositories{
+		Users:        s.Users(),
+		Events:       s.Events(),
+		Availability: s.Availability(),
+		Credentials:  s.Credentials(),
+		MagicLinks:   s.MagicLinks(),
+	}
+}
+
+// Close saves the data file if the command changed it.
+func (b *storeBackend) Close(ctx context.Context) error {
+	return b.store.Close(ctx)
+}
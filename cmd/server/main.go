@@ -1,25 +1,181 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"meeting-scheduler/internal/config"
 	"meeting-scheduler/internal/handler"
+	"meeting-scheduler/internal/logging"
+	"meeting-scheduler/internal/metrics"
 	"meeting-scheduler/internal/repository"
 	"meeting-scheduler/internal/service"
+	"meeting-scheduler/internal/tracing"
+	"meeting-scheduler/internal/version"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// trashPurgeInterval is how often deleted events past their retention
+// period are removed.
+const trashPurgeInterval = time.Hour
+
+// readHeaderTimeout stops clients from holding connections open by sending
+// request headers slowly.
+const readHeaderTimeout = 10 * time.Second
+
+// recordCountMaxAge is how long the record counts exported on /metrics are
+// reused before the store is scanned again.
+const recordCountMaxAge = 30 * time.Second
+
+//go:generate go run github.com/swaggo/swag/cmd/swag@v1.16.4 init --dir ../.. --generalInfo cmd/server/main.go --output ../../docs --outputTypes go --parseInternal
+
+// @title Meeting Scheduler API
+// @version 1.0
+// @description Collects participants' availability for events and suggests the time slots that suit most of them.
+// @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description An API key issued at sign-up or by POST /api/v1/me/api-keys. It may also be sent as "Authorization: Bearer <key>".
 func main() {
-	r := gin.Default()
+	cfg, err := config.Load(os.Args[1:], os.LookupEnv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	level, _ := logging.ParseLevel(cfg.LogLevel) // checked by config.Load
+	logger := logging.New(os.Stdout, level)
+	slog.SetDefault(logger)
+	gin.SetMode(cfg.Mode)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		File:        cfg.Tracing.File,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: "meeting-scheduler",
+		Version:     version.String(),
+	})
+	if err != nil {
+		fatal("setting up tracing", "error", err)
+	}
+
+	// The first SIGINT or SIGTERM starts a graceful shutdown; a second one
+	// kills the process.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	m := metrics.New()
+	r := gin.New()
+	r.Use(handler.RequestLogger(logger), tracing.Middleware(), m.Middleware(), handler.Recoverer(logger), handler.CORS(cfg.CORS.Origins))
 
-	eventRepo := repository.NewInMemoryEventRepository()
-	availabilityRepo := repository.NewInMemoryAvailabilityRepository()
-	userRepo := repository.NewInMemoryUserRepository()
-	svc := service.NewSchedulerService(userRepo, eventRepo, availabilityRepo)
+	store, err := openStore(cfg.Storage)
+	if err != nil {
+		fatal("opening storage", "error", err)
+	}
+	eventRepo := tracing.TraceEventRepository(m.InstrumentEventRepository(store.Events()))
+	availabilityRepo := tracing.TraceAvailabilityRepository(m.InstrumentAvailabilityRepository(store.Availability()))
+	userRepo := tracing.TraceUserRepository(m.InstrumentUserRepository(store.Users()))
+	credentialRepo := tracing.TraceCredentialRepository(m.InstrumentCredentialRepository(store.Credentials()))
+	linkRepo := tracing.TraceMagicLinkRepository(m.InstrumentMagicLinkRepository(store.MagicLinks()))
+	auditRepo := tracing.TraceAuditRepository(m.InstrumentAuditRepository(store.Audit()))
+	versionRepo := tracing.TraceVersionRepository(m.InstrumentVersionRepository(store.Versions()))
+	opts := []service.Option{
+		service.WithCredentials(credentialRepo),
+		service.WithMagicLinks(linkRepo),
+		service.WithLinkSecret([]byte(cfg.Auth.MagicLinkSecret)),
+		service.WithAuditLog(auditRepo),
+		service.WithVersions(versionRepo),
+		service.WithAdmins(cfg.Auth.Admins...),
+		service.WithSuggestionStep(cfg.SuggestionStep),
+		service.WithTrashRetention(cfg.TrashRetention),
+		service.WithLogger(logger),
+		service.WithObserver(m),
+	}
+	svc := service.NewSchedulerService(userRepo, eventRepo, availabilityRepo, opts...)
+	var workers sync.WaitGroup
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		svc.RunTrashPurger(ctx, trashPurgeInterval)
+	}()
+	if cfg.Storage.Backend == config.BackendFile {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			store.RunSnapshots(ctx, cfg.Storage.SnapshotInterval, logger)
+		}()
+	}
+	m.CountRecords(svc.CountRecords, recordCountMaxAge)
 	h := handler.NewHandler(svc)
 
 	h.RegisterRoutes(r)
+	r.GET("/metrics", gin.WrapH(m.Handler()))
+
+	srv := &http.Server{Addr: cfg.Addr, Handler: r, ReadHeaderTimeout: readHeaderTimeout}
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	logger.Info("server running", "addr", cfg.Addr, "storage", cfg.Storage.Backend, "version", version.String())
+
+	failed := false
+	select {
+	case err := <-serveErr:
+		logger.Error("server stopped", "error", err)
+		failed = true
+	case <-ctx.Done():
+		stop()
+		logger.Info("shutting down", "drain_delay", cfg.DrainDelay.String(), "timeout", cfg.ShutdownTimeout.String())
+		h.Drain()
+		time.Sleep(cfg.DrainDelay)
+	}
+
+	// Stop taking requests and wait for those in flight, then stop the
+	// background workers and flush storage and traces, all within the
+	// shutdown timeout.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("draining requests", "error", err)
+		srv.Close()
+		failed = true
+	}
+	stop()
+	workers.Wait()
+	if err := repository.Close(shutdownCtx, store); err != nil {
+		logger.Error("closing repositories", "error", err)
+		failed = true
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("flushing traces", "error", err)
+		failed = true
+	}
+	if failed {
+		os.Exit(1)
+	}
+	logger.Info("server stopped")
+}
+
+// openStore returns the repositories of the configured backend.
+func openStore(cfg config.Storage) (*repository.MemoryStore, error) {
+	if cfg.Backend == config.BackendFile {
+		return repository.OpenMemoryStore(cfg.DSN, repository.WithJournal(cfg.DSN+".journal", cfg.JournalCompactSize))
+	}
+	return repository.NewMemoryStore(), nil
+}
 
-	log.Println("Server running on :8080")
-	r.Run(":8080")
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
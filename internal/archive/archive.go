@@ -0,0 +1,419 @@
+// Package archive exports a server's users, events, availability and
+// credentials as one versioned JSON document and imports such documents
+// back. The admin API and
+// schedctl both go through it.
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"sort"
+	"time"
+)
+
+// ErrInvalid wraps the reasons an archive or import request is rejected
+// before anything is written.
+var ErrInvalid = errors.New("invalid archive")
+
+// Repositories are what an archive is taken from and imported into. Every
+// write of an import goes through UnitOfWork, so a failed import changes
+// nothing, except that magic links of the events it deletes are deleted
+// once it has committed.
+type Repositories struct {
+	Users        repository.UserRepository
+	Events       repository.EventRepository
+	Availability repository.AvailabilityRepository
+	Credentials  repository.CredentialRepository
+	MagicLinks   repository.MagicLinkRepository
+	UnitOfWork   repository.UnitOfWork
+}
+
+// Validator checks archived events and availability with the rules the
+// service applies to its own writes. users holds every user the server has
+// once the archive is imported, and e is the event av belongs to as it is
+// then.
+type Validator interface {
+	ValidateEvent(e *model.Event, users map[string]bool) error
+	ValidateAvailability(av model.Availability, e *model.Event, users map[string]bool) error
+}
+
+// Export returns everything in repos, sorted by ID, as an archive taken at
+// now. Availability of events that no longer exist is left out.
+func Export(ctx context.Context, repos Repositories, now time.Time) (*model.Archive, error) {
+	a := &model.Archive{
+		Kind:         model.ArchiveKind,
+		Version:      model.ArchiveVersion,
+		ExportedAt:   now.UTC(),
+		Users:        []model.User{},
+		Events:       []model.Event{},
+		Availability: []model.Availability{},
+		Credentials:  []model.ArchivedCredential{},
+	}
+	users, err := repos.Users.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		a.Users = append(a.Users, *u)
+	}
+	credentials, err := allCredentials(ctx, repos.Credentials, users)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range credentials {
+		a.Credentials = append(a.Credentials, model.ArchivedCredential{Credential: *c, KeyHash: c.KeyHash})
+	}
+	events, err := allEvents(ctx, repos.Events)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		a.Events = append(a.Events, *e)
+		availability, err := repos.Availability.GetByEvent(ctx, e.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, av := range availability {
+			a.Availability = append(a.Availability, av)
+		}
+	}
+	sortArchive(a)
+	return a, nil
+}
+
+// allEvents lists live and trashed events alike.
+func allEvents(ctx context.Context, events repository.EventRepository) ([]*model.Event, error) {
+	live, err := events.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	trashed, err := events.ListTrashed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append(live, trashed...), nil
+}
+
+// allCredentials lists the credentials of every user in users.
+func allCredentials(ctx context.Context, credentials repository.CredentialRepository, users map[string]*model.User) ([]*model.Credential, error) {
+	var all []*model.Credential
+	for id := range users {
+		creds, err := credentials.ListByUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, creds...)
+	}
+	return all, nil
+}
+
+func sortArchive(a *model.Archive) {
+	sort.Slice(a.Users, func(i, j int) bool { return a.Users[i].ID < a.Users[j].ID })
+	sort.Slice(a.Credentials, func(i, j int) bool { return a.Credentials[i].ID < a.Credentials[j].ID })
+	sort.Slice(a.Events, func(i, j int) bool { return a.Events[i].ID < a.Events[j].ID })
+	sort.Slice(a.Availability, func(i, j int) bool {
+		return availabilityKey(a.Availability[i]) < availabilityKey(a.Availability[j])
+	})
+}
+
+// Write encodes a as indented JSON.
+func Write(w io.Writer, a *model.Archive) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a)
+}
+
+// Decode reads an archive and checks that this server understands it.
+func Decode(r io.Reader) (*model.Archive, error) {
+	var a model.Archive
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&a); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+	switch {
+	case a.Kind != model.ArchiveKind:
+		return nil, fmt.Errorf("%w: kind is %q, expected %q", ErrInvalid, a.Kind, model.ArchiveKind)
+	case a.Version < 1:
+		return nil, fmt.Errorf("%w: no valid version", ErrInvalid)
+	case a.Version > model.ArchiveVersion:
+		return nil, fmt.Errorf("%w: version %d is newer than the supported version %d", ErrInvalid, a.Version, model.ArchiveVersion)
+	}
+	return &a, nil
+}
+
+// Import brings the contents of a into repos as described by mode, one of
+// model.ImportMerge and model.ImportReplace. Events and availability that v
+// rejects make the whole archive invalid. With dryRun set nothing is written
+// and the report says what would have been. Events deleted in replace mode
+// are purged as the trash purges them, versions and magic links included.
+func Import(ctx context.Context, repos Repositories, v Validator, a *model.Archive, mode string, dryRun bool) (*model.ImportReport, error) {
+	if mode != model.ImportMerge && mode != model.ImportReplace {
+		return nil, fmt.Errorf("%w: unknown import mode %q, expected merge or replace", ErrInvalid, mode)
+	}
+	p, err := newPlan(ctx, repos, v, a, mode)
+	if err != nil {
+		return nil, err
+	}
+	p.report.DryRun = dryRun
+	if dryRun {
+		return &p.report, nil
+	}
+	err = repos.UnitOfWork.Do(ctx, func(ctx context.Context, tx repository.Tx) error {
+		users, events, availability, credentials := tx.Users(), tx.Events(), tx.Availability(), tx.Credentials()
+		for _, u := range p.putUsers {
+			if err := users.Create(ctx, &u); err != nil {
+				return fmt.Errorf("importing user %s: %w", u.ID, err)
+			}
+		}
+		for _, c := range p.createCredentials {
+			if err := credentials.Create(ctx, &c); err != nil {
+				return fmt.Errorf("importing credential %s: %w", c.ID, err)
+			}
+		}
+		for _, av := range p.deleteAvailability {
+			if err := availability.Delete(ctx, av.EventID, av.UserID); err != nil {
+				return err
+			}
+		}
+		for _, id := range p.deleteEvents {
+			if err := repository.PurgeEvent(ctx, events, availability, tx.Versions(), id); err != nil {
+				return err
+			}
+		}
+		for _, e := range p.putEvents {
+			// Create overwrites, and unlike Update it also reaches trashed
+			// events and keeps an archived event's trash state.
+			if err := events.Create(ctx, &e); err != nil {
+				return err
+			}
+		}
+		for _, av := range p.createAvailability {
+			if err := availability.Create(ctx, av); err != nil {
+				return err
+			}
+		}
+		for _, av := range p.updateAvailability {
+			if err := availability.Update(ctx, av); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("importing archive: %w", err)
+	}
+	for _, id := range p.deleteEvents {
+		if err := repos.MagicLinks.DeleteByEvent(ctx, id); err != nil {
+			return nil, fmt.Errorf("deleting magic links of event %s: %w", id, err)
+		}
+	}
+	return &p.report, nil
+}
+
+// plan is the set of writes an import makes.
+type plan struct {
+	report             model.ImportReport
+	putUsers           []model.User
+	createCredentials  []model.Credential
+	putEvents          []model.Event
+	deleteEvents       []string
+	createAvailability []model.Availability
+	updateAvailability []model.Availability
+	deleteAvailability []model.Availability
+}
+
+// newPlan compares a with what repos hold, checks its records with v and
+// works out the writes that import it.
+func newPlan(ctx context.Context, repos Repositories, v Validator, a *model.Archive, mode string) (*plan, error) {
+	replace := mode == model.ImportReplace
+	p := &plan{report: model.ImportReport{Mode: mode, Conflicts: []model.ImportConflict{}}}
+	resolution := "kept"
+	if replace {
+		resolution = "replaced"
+	}
+	// compare counts the record in c and reports whether it must be written.
+	compare := func(c *model.ImportCounts, entity, id string, existing, incoming any, exists bool) bool {
+		switch {
+		case !exists:
+			c.Created++
+			return true
+		case sameJSON(existing, incoming):
+			c.Unchanged++
+			return false
+		}
+		p.report.Conflicts = append(p.report.Conflicts, model.ImportConflict{Entity: entity, ID: id, Resolution: resolution})
+		if replace {
+			c.Updated++
+			return true
+		}
+		c.Kept++
+		return false
+	}
+
+	users, err := repos.Users.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Users are never removed, so these are the users after the import.
+	userIDs := map[string]bool{}
+	for id := range users {
+		userIDs[id] = true
+	}
+	seen := map[string]bool{}
+	for _, u := range a.Users {
+		if u.ID == "" || seen[u.ID] {
+			return nil, fmt.Errorf("%w: empty or repeated user ID %q", ErrInvalid, u.ID)
+		}
+		seen[u.ID] = true
+		userIDs[u.ID] = true
+		existing, ok := users[u.ID]
+		if compare(&p.report.Users, model.EntityUser, u.ID, existing, u, ok) {
+			p.putUsers = append(p.putUsers, u)
+		}
+	}
+
+	existingCredentials, err := allCredentials(ctx, repos.Credentials, users)
+	if err != nil {
+		return nil, err
+	}
+	credentials, keys := map[string]model.ArchivedCredential{}, map[string]string{}
+	for _, c := range existingCredentials {
+		credentials[c.ID] = model.ArchivedCredential{Credential: *c, KeyHash: c.KeyHash}
+		keys[c.KeyHash] = c.ID
+	}
+	archivedCredentials := map[string]bool{}
+	for _, c := range a.Credentials {
+		if c.ID == "" || c.KeyHash == "" || archivedCredentials[c.ID] {
+			return nil, fmt.Errorf("%w: credential %q has no ID or key hash or is repeated", ErrInvalid, c.ID)
+		}
+		archivedCredentials[c.ID] = true
+		if !userIDs[c.UserID] {
+			return nil, fmt.Errorf("%w: credential %s is for user %q, who would not exist", ErrInvalid, c.ID, c.UserID)
+		}
+		// Credentials are only ever added: one that differs from the
+		// credential of the same ID here, or reuses another's key, is
+		// rejected rather than kept or replaced.
+		existing, ok := credentials[c.ID]
+		switch {
+		case ok && !sameJSON(existing, c):
+			return nil, fmt.Errorf("%w: credential %s differs from the one with that ID here", ErrInvalid, c.ID)
+		case ok:
+			p.report.Credentials.Unchanged++
+		case keys[c.KeyHash] != "":
+			return nil, fmt.Errorf("%w: the key of credential %s is already used by credential %s", ErrInvalid, c.ID, keys[c.KeyHash])
+		default:
+			keys[c.KeyHash] = c.ID
+			cred := c.Credential
+			cred.KeyHash = c.KeyHash
+			p.createCredentials = append(p.createCredentials, cred)
+			p.report.Credentials.Created++
+		}
+	}
+
+	existingEvents, err := allEvents(ctx, repos.Events)
+	if err != nil {
+		return nil, err
+	}
+	events := map[string]*model.Event{}
+	for _, e := range existingEvents {
+		events[e.ID] = e
+	}
+	archived := map[string]bool{}
+	// after holds the events as they are once the archive is imported.
+	after := maps.Clone(events)
+	for _, e := range a.Events {
+		if e.ID == "" || archived[e.ID] {
+			return nil, fmt.Errorf("%w: empty or repeated event ID %q", ErrInvalid, e.ID)
+		}
+		archived[e.ID] = true
+		if err := v.ValidateEvent(&e, userIDs); err != nil {
+			return nil, fmt.Errorf("%w: event %s: %w", ErrInvalid, e.ID, err)
+		}
+		existing, ok := events[e.ID]
+		if compare(&p.report.Events, model.EntityEvent, e.ID, existing, e, ok) {
+			p.putEvents = append(p.putEvents, e)
+			after[e.ID] = &e
+		}
+	}
+
+	availability := map[string]model.Availability{}
+	for id := range events {
+		existing, err := repos.Availability.GetByEvent(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, av := range existing {
+			availability[availabilityKey(av)] = av
+		}
+	}
+	keep := map[string]bool{}
+	for _, av := range a.Availability {
+		key := availabilityKey(av)
+		if av.EventID == "" || av.UserID == "" || keep[key] {
+			return nil, fmt.Errorf("%w: incomplete or repeated availability entry %q", ErrInvalid, key)
+		}
+		keep[key] = true
+		if _, ok := events[av.EventID]; !archived[av.EventID] && (replace || !ok) {
+			return nil, fmt.Errorf("%w: availability %q is for an event that would not exist", ErrInvalid, key)
+		}
+		if err := v.ValidateAvailability(av, after[av.EventID], userIDs); err != nil {
+			return nil, fmt.Errorf("%w: availability %q: %w", ErrInvalid, key, err)
+		}
+		existing, ok := availability[key]
+		if !compare(&p.report.Availability, model.EntityAvailability, key, existing, av, ok) {
+			continue
+		}
+		if ok {
+			p.updateAvailability = append(p.updateAvailability, av)
+		} else {
+			p.createAvailability = append(p.createAvailability, av)
+		}
+	}
+
+	if replace {
+		for id := range events {
+			if !archived[id] {
+				p.deleteEvents = append(p.deleteEvents, id)
+				p.report.Events.Deleted++
+			}
+		}
+		for key, av := range availability {
+			if !keep[key] {
+				p.deleteAvailability = append(p.deleteAvailability, av)
+				p.report.Availability.Deleted++
+			}
+		}
+		sort.Strings(p.deleteEvents)
+		sort.Slice(p.deleteAvailability, func(i, j int) bool {
+			return availabilityKey(p.deleteAvailability[i]) < availabilityKey(p.deleteAvailability[j])
+		})
+	}
+	sort.Slice(p.report.Conflicts, func(i, j int) bool {
+		ci, cj := p.report.Conflicts[i], p.report.Conflicts[j]
+		if ci.Entity != cj.Entity {
+			return ci.Entity < cj.Entity
+		}
+		return ci.ID < cj.ID
+	})
+	return p, nil
+}
+
+func availabilityKey(av model.Availability) string {
+	return av.EventID + "/" + av.UserID
+}
+
+// sameJSON reports whether a and b encode to the same JSON, so records are
+// compared as they appear in an archive.
+func sameJSON(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ja, jb)
+}
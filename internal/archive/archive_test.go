@@ -0,0 +1,289 @@
+package archive_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"meeting-scheduler/internal/archive"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exportedAt = time.Date(2025, time.May, 20, 9, 0, 0, 0, time.UTC)
+
+func newRepositories() archive.Repositories {
+	return withVersions(archive.Repositories{
+		Users:        repository.NewInMemoryUserRepository(),
+		Events:       repository.NewInMemoryEventRepository(),
+		Availability: repository.NewInMemoryAvailabilityRepository(),
+		Credentials:  repository.NewInMemoryCredentialRepository(),
+		MagicLinks:   repository.NewInMemoryMagicLinkRepository(),
+	}, repository.NewInMemoryVersionRepository())
+}
+
+// withVersions sets the unit of work of repos to one that keeps event
+// versions in versions.
+func withVersions(repos archive.Repositories, versions repository.VersionRepository) archive.Repositories {
+	repos.UnitOfWork = repository.NewInMemoryUnitOfWork(repos.Users, repos.Events, repos.Availability,
+		repos.Credentials, repository.NewInMemoryAuditRepository(), versions)
+	return repos
+}
+
+func importArchive(ctx context.Context, repos archive.Repositories, a *model.Archive, mode string, dryRun bool) (*model.ImportReport, error) {
+	return archive.Import(ctx, repos, service.ArchiveValidator{}, a, mode, dryRun)
+}
+
+// seeded returns repositories holding users u1 and u2, an API key of u1, a
+// live event e1 with availability from both and a trashed event e2 with
+// availability from u1.
+func seeded(t *testing.T) archive.Repositories {
+	t.Helper()
+	ctx := t.Context()
+	repos := newRepositories()
+	require.NoError(t, repos.Users.Create(ctx, &model.User{ID: "u1", Name: "Alice"}))
+	require.NoError(t, repos.Users.Create(ctx, &model.User{ID: "u2", Name: "Bob"}))
+	require.NoError(t, repos.Credentials.Create(ctx, &model.Credential{ID: "c1", UserID: "u1", KeyHash: "hash1", CreatedAt: exportedAt}))
+	require.NoError(t, repos.Events.Create(ctx, &model.Event{ID: "e1", Title: "Planning", Organizer: "u1", Participants: []string{"u1", "u2"}}))
+	require.NoError(t, repos.Events.Create(ctx, &model.Event{ID: "e2", Title: "Old", Organizer: "u1", Participants: []string{"u1"}}))
+	require.NoError(t, repos.Availability.Create(ctx, model.Availability{EventID: "e1", UserID: "u1"}))
+	require.NoError(t, repos.Availability.Create(ctx, model.Availability{EventID: "e1", UserID: "u2"}))
+	require.NoError(t, repos.Availability.Create(ctx, model.Availability{EventID: "e2", UserID: "u1"}))
+	require.NoError(t, repos.Events.Trash(ctx, "e2", exportedAt.Add(-time.Hour)))
+	return repos
+}
+
+func export(t *testing.T, repos archive.Repositories) *model.Archive {
+	t.Helper()
+	a, err := archive.Export(t.Context(), repos, exportedAt)
+	require.NoError(t, err)
+	return a
+}
+
+func TestExport_RoundTrip(t *testing.T) {
+	a := export(t, seeded(t))
+	require.Len(t, a.Users, 2)
+	require.Len(t, a.Events, 2)
+	require.Len(t, a.Availability, 3)
+	require.Len(t, a.Credentials, 1)
+	assert.Equal(t, "hash1", a.Credentials[0].KeyHash)
+	assert.NotNil(t, a.Events[1].DeletedAt, "trashed events are exported")
+
+	var buf bytes.Buffer
+	require.NoError(t, archive.Write(&buf, a))
+	decoded, err := archive.Decode(&buf)
+	require.NoError(t, err)
+
+	restored := newRepositories()
+	report, err := importArchive(t.Context(), restored, decoded, model.ImportReplace, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.ImportCounts{Created: 2}, report.Events)
+	assert.Equal(t, model.ImportCounts{Created: 3}, report.Availability)
+	assert.Equal(t, model.ImportCounts{Created: 1}, report.Credentials)
+	assert.Equal(t, a, export(t, restored))
+	cred, err := restored.Credentials.GetByKeyHash(t.Context(), "hash1")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", cred.UserID, "imported keys keep working")
+
+	trashed, err := restored.Events.ListTrashed(t.Context())
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.Equal(t, "e2", trashed[0].ID)
+}
+
+func TestImport_MergeKeepsConflictsAndReplaceOverwrites(t *testing.T) {
+	ctx := t.Context()
+	a := export(t, seeded(t))
+	a.Users[0].Name = "Alicia"
+	a.Events[0].Title = "Kickoff"
+	a.Events = append(a.Events, model.Event{ID: "e3", Title: "New", Organizer: "u2", Participants: []string{"u2"}})
+	a.Availability = a.Availability[:1]
+
+	repos := seeded(t)
+	report, err := importArchive(ctx, repos, a, model.ImportMerge, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.ImportCounts{Kept: 1, Unchanged: 1}, report.Users)
+	assert.Equal(t, model.ImportCounts{Created: 1, Kept: 1, Unchanged: 1}, report.Events)
+	assert.Equal(t, model.ImportCounts{Unchanged: 1}, report.Availability)
+	assert.Equal(t, model.ImportCounts{Unchanged: 1}, report.Credentials)
+	assert.Equal(t, []model.ImportConflict{
+		{Entity: model.EntityEvent, ID: "e1", Resolution: "kept"},
+		{Entity: model.EntityUser, ID: "u1", Resolution: "kept"},
+	}, report.Conflicts)
+	e1, err := repos.Events.Get(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Planning", e1.Title)
+	entries, err := repos.Availability.GetByEvent(ctx, "e1")
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "merging deletes nothing")
+
+	report, err = importArchive(ctx, repos, a, model.ImportReplace, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.ImportCounts{Updated: 1, Unchanged: 2}, report.Events)
+	assert.Equal(t, model.ImportCounts{Unchanged: 1, Deleted: 2}, report.Availability)
+	e1, err = repos.Events.Get(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Kickoff", e1.Title)
+	u1, err := repos.Users.Get(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "Alicia", u1.Name)
+	entries, err = repos.Availability.GetByEvent(ctx, "e1")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	entries, err = repos.Availability.GetByEvent(ctx, "e2")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestImport_DryRunWritesNothing(t *testing.T) {
+	ctx := t.Context()
+	repos := newRepositories()
+	report, err := importArchive(ctx, repos, export(t, seeded(t)), model.ImportMerge, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, model.ImportCounts{Created: 2}, report.Users)
+	assert.Equal(t, model.ImportCounts{Created: 3}, report.Availability)
+
+	users, err := repos.Users.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+	events, err := repos.Events.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	trashed, err := repos.Events.ListTrashed(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, trashed)
+}
+
+func TestImport_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		mode   string
+		change func(a *model.Archive)
+	}{
+		{"unknown mode", "overwrite", func(*model.Archive) {}},
+		{"repeated user", model.ImportMerge, func(a *model.Archive) { a.Users = append(a.Users, a.Users[0]) }},
+		{"event without ID", model.ImportMerge, func(a *model.Archive) { a.Events[0].ID = "" }},
+		{"availability without user", model.ImportMerge, func(a *model.Archive) { a.Availability[0].UserID = "" }},
+		{"availability for unknown event", model.ImportMerge, func(a *model.Archive) { a.Availability[0].EventID = "nope" }},
+		{"availability for a replaced event", model.ImportReplace, func(a *model.Archive) { a.Events = a.Events[1:] }},
+		{"event without participants", model.ImportMerge, func(a *model.Archive) { a.Events[0].Participants = nil }},
+		{"event with unknown participant", model.ImportMerge, func(a *model.Archive) { a.Events[0].Participants = []string{"u1", "u9"} }},
+		{"event with unknown organizer", model.ImportMerge, func(a *model.Archive) { a.Events[0].Organizer = "u9" }},
+		{"event slot ending before it starts", model.ImportMerge, func(a *model.Archive) {
+			a.Events[0].Slots = []model.Slot{{Start: exportedAt, End: exportedAt.Add(-time.Hour)}}
+		}},
+		{"event with invalid quorum", model.ImportMerge, func(a *model.Archive) { a.Events[0].Quorum = &model.Quorum{MinCount: 5} }},
+		{"availability of unknown user", model.ImportMerge, func(a *model.Archive) { a.Availability[0].UserID = "u9" }},
+		{"declined availability with slots", model.ImportMerge, func(a *model.Archive) {
+			a.Availability[0].Declined = true
+			a.Availability[0].Slots = []model.Slot{{Start: exportedAt, End: exportedAt.Add(time.Hour)}}
+		}},
+		{"credential of unknown user", model.ImportMerge, func(a *model.Archive) { a.Credentials[0].UserID = "u9" }},
+		{"credential changed", model.ImportMerge, func(a *model.Archive) { a.Credentials[0].KeyHash = "other" }},
+		{"credential reusing a key", model.ImportMerge, func(a *model.Archive) { a.Credentials[0].ID = "c2" }},
+		{"credential without key", model.ImportMerge, func(a *model.Archive) {
+			a.Credentials = append(a.Credentials, model.ArchivedCredential{Credential: model.Credential{ID: "c2", UserID: "u1"}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := seeded(t)
+			a := export(t, repos)
+			tt.change(a)
+			_, err := importArchive(t.Context(), repos, a, tt.mode, true)
+			assert.ErrorIs(t, err, archive.ErrInvalid, "even a dry run is rejected")
+		})
+	}
+}
+
+// failingAvailability fails every Create.
+type failingAvailability struct {
+	repository.AvailabilityRepository
+}
+
+func (failingAvailability) Create(context.Context, model.Availability) error {
+	return errors.New("disk full")
+}
+
+func TestImport_RollsBackOnFailure(t *testing.T) {
+	ctx := t.Context()
+	a := export(t, seeded(t))
+	a.Users[0].Name = "Alicia"
+	a.Users = append(a.Users, model.User{ID: "u3", Name: "Carol"})
+	a.Credentials = append(a.Credentials, model.ArchivedCredential{Credential: model.Credential{ID: "c3", UserID: "u3"}, KeyHash: "hash3"})
+	a.Events[0].Title = "Kickoff"
+
+	repos := seeded(t)
+	require.NoError(t, repos.Availability.Delete(ctx, "e1", "u2"))
+	failing := repos
+	failing.Availability = failingAvailability{repos.Availability}
+	repos.UnitOfWork = withVersions(failing, repository.NewInMemoryVersionRepository()).UnitOfWork
+	_, err := importArchive(ctx, repos, a, model.ImportReplace, false)
+	require.ErrorContains(t, err, "disk full")
+
+	e1, err := repos.Events.Get(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Planning", e1.Title, "the event update is rolled back")
+	users, err := repos.Users.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*model.User{"u1": {ID: "u1", Name: "Alice"}, "u2": {ID: "u2", Name: "Bob"}}, users,
+		"user writes are rolled back too")
+	_, err = repos.Credentials.GetByKeyHash(ctx, "hash3")
+	assert.Error(t, err, "and so are new credentials")
+}
+
+func TestImport_ReplacePurgesDeletedEvents(t *testing.T) {
+	ctx := t.Context()
+	versions := repository.NewInMemoryVersionRepository()
+	repos := withVersions(seeded(t), versions)
+	a := export(t, repos)
+	a.Events, a.Availability = a.Events[:1], a.Availability[:2]
+	require.NoError(t, versions.Append(ctx, &model.EventVersion{EventID: "e2"}))
+	require.NoError(t, repos.MagicLinks.Create(ctx, &model.MagicLink{ID: "l1", EventID: "e2"}))
+
+	report, err := importArchive(ctx, repos, a, model.ImportReplace, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.ImportCounts{Unchanged: 1, Deleted: 1}, report.Events)
+	_, err = repos.Events.GetTrashed(ctx, "e2")
+	assert.Error(t, err)
+	history, err := versions.List(ctx, "e2")
+	require.NoError(t, err)
+	assert.Empty(t, history, "versions of deleted events are purged")
+	links, err := repos.MagicLinks.ListByEvent(ctx, "e2")
+	require.NoError(t, err)
+	assert.Empty(t, links, "and so are their magic links")
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"current version", `{"kind":"meeting-scheduler.archive","version":2}`, ""},
+		{"version without credentials", `{"kind":"meeting-scheduler.archive","version":1}`, ""},
+		{"other kind", `{"kind":"snapshot","version":1}`, `kind is "snapshot"`},
+		{"no version", `{"kind":"meeting-scheduler.archive"}`, "no valid version"},
+		{"newer version", `{"kind":"meeting-scheduler.archive","version":3}`, "version 3 is newer"},
+		{"unknown field", `{"kind":"meeting-scheduler.archive","version":1,"extra":true}`, "unknown field"},
+		{"not JSON", `users: []`, "invalid character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := archive.Decode(strings.NewReader(tt.input))
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, archive.ErrInvalid)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
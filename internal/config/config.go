@@ -0,0 +1,353 @@
+// Package config loads the server's settings. Each setting comes from, in
+// increasing order of precedence, its default, the YAML config file, its
+// environment variable and its command-line flag.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"meeting-scheduler/internal/logging"
+	"meeting-scheduler/internal/tracing"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Storage backends accepted in Storage.Backend. The file backend keeps data
+// in memory and persists it as a JSON snapshot at the DSN path, logging each
+// write to a journal beside it (the DSN with ".journal" appended) in between.
+const (
+	BackendMemory = "memory"
+	BackendFile   = "file"
+)
+
+// Gin modes accepted in Config.Mode.
+const (
+	ModeDebug   = "debug"
+	ModeRelease = "release"
+	ModeTest    = "test"
+)
+
+// minLinkSecret is the shortest magic link secret accepted, in bytes.
+const minLinkSecret = 32
+
+type Config struct {
+	// Addr is the host:port the HTTP server listens on.
+	Addr string `yaml:"addr"`
+	// Mode is the gin mode: debug, release or test.
+	Mode     string `yaml:"mode"`
+	LogLevel string `yaml:"log_level"`
+	// SuggestionStep is how far apart candidate window start times are.
+	SuggestionStep time.Duration `yaml:"suggestion_step"`
+	TrashRetention time.Duration `yaml:"trash_retention"`
+	// DrainDelay is how long the server keeps serving after reporting itself
+	// unavailable on shutdown, giving load balancers time to notice.
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// ShutdownTimeout bounds draining in-flight requests, stopping background
+	// work and flushing storage once the server stops accepting requests.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	Storage         Storage       `yaml:"storage"`
+	CORS            CORS          `yaml:"cors"`
+	Auth            Auth          `yaml:"auth"`
+	Tracing         Tracing       `yaml:"tracing"`
+}
+
+type Storage struct {
+	Backend string `yaml:"backend"`
+	DSN     string `yaml:"dsn"`
+	// SnapshotInterval is how often the file backend saves a snapshot, in
+	// addition to saving one on shutdown.
+	SnapshotInterval time.Duration `yaml:"snapshot_interval"`
+	// JournalCompactSize is the size in bytes at which the file backend's
+	// journal is folded into a fresh snapshot.
+	JournalCompactSize int64 `yaml:"journal_compact_size"`
+}
+
+type CORS struct {
+	// Origins may call the API from a browser; "*" allows any origin.
+	Origins []string `yaml:"origins"`
+}
+
+type Auth struct {
+	// MagicLinkSecret signs guest links. When empty a random secret is
+	// generated at startup, so links stop working after a restart.
+	MagicLinkSecret string   `yaml:"magic_link_secret"`
+	Admins          []string `yaml:"admins"`
+}
+
+type Tracing struct {
+	Exporter string `yaml:"exporter"`
+	File     string `yaml:"file"`
+	Endpoint string `yaml:"endpoint"`
+	Insecure bool   `yaml:"insecure"`
+}
+
+// Default returns the settings used when nothing overrides them.
+func Default() *Config {
+	return &Config{
+		Addr:            ":8080",
+		Mode:            ModeRelease,
+		LogLevel:        "info",
+		SuggestionStep:  15 * time.Minute,
+		TrashRetention:  30 * 24 * time.Hour,
+		ShutdownTimeout: 15 * time.Second,
+		Storage:         Storage{Backend: BackendMemory, SnapshotInterval: 5 * time.Minute, JournalCompactSize: 4 << 20},
+		Tracing:         Tracing{Exporter: tracing.ExporterNone},
+	}
+}
+
+// setting binds one field to its flag and environment variable. Secrets
+// have no flag, as command lines are visible to other users of the host.
+type setting struct {
+	flag, env, usage string
+	boolean          bool
+	set              func(c *Config, v string) error
+}
+
+var settings = []setting{
+	{flag: "addr", env: "LISTEN_ADDR", usage: "host:port to listen on",
+		set: func(c *Config, v string) error { c.Addr = v; return nil }},
+	{flag: "mode", env: "GIN_MODE", usage: "gin mode: debug, release or test",
+		set: func(c *Config, v string) error { c.Mode = v; return nil }},
+	{flag: "log-level", env: "LOG_LEVEL", usage: "minimum log level: debug, info, warn or error",
+		set: func(c *Config, v string) error { c.LogLevel = v; return nil }},
+	{flag: "suggestion-step", env: "SUGGESTION_STEP", usage: "spacing of candidate window start times, such as 15m",
+		set: func(c *Config, v string) error { return setDuration(&c.SuggestionStep, v) }},
+	{flag: "trash-retention", env: "TRASH_RETENTION", usage: "how long deleted events are kept, such as 720h",
+		set: func(c *Config, v string) error { return setDuration(&c.TrashRetention, v) }},
+	{flag: "drain-delay", env: "DRAIN_DELAY", usage: "how long to keep serving after reporting unavailable on shutdown",
+		set: func(c *Config, v string) error { return setDuration(&c.DrainDelay, v) }},
+	{flag: "shutdown-timeout", env: "SHUTDOWN_TIMEOUT", usage: "how long shutting down may take before connections are cut",
+		set: func(c *Config, v string) error { return setDuration(&c.ShutdownTimeout, v) }},
+	{flag: "storage", env: "STORAGE_BACKEND", usage: "storage backend: memory or file",
+		set: func(c *Config, v string) error { c.Storage.Backend = v; return nil }},
+	{flag: "storage-dsn", env: "STORAGE_DSN", usage: "data source name of the storage backend; the snapshot path for file",
+		set: func(c *Config, v string) error { c.Storage.DSN = v; return nil }},
+	{flag: "snapshot-interval", env: "SNAPSHOT_INTERVAL", usage: "how often the file backend saves a snapshot",
+		set: func(c *Config, v string) error { return setDuration(&c.Storage.SnapshotInterval, v) }},
+	{flag: "journal-compact-size", env: "JOURNAL_COMPACT_SIZE", usage: "journal size in bytes at which the file backend takes a snapshot",
+		set: func(c *Config, v string) error {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("expected a number of bytes")
+			}
+			c.Storage.JournalCompactSize = n
+			return nil
+		}},
+	{flag: "cors-origins", env: "CORS_ORIGINS", usage: "comma separated origins allowed to call the API, or *",
+		set: func(c *Config, v string) error { c.CORS.Origins = splitList(v); return nil }},
+	{env: "MAGIC_LINK_SECRET",
+		set: func(c *Config, v string) error { c.Auth.MagicLinkSecret = v; return nil }},
+	{flag: "admins", env: "ADMIN_USER_IDS", usage: "comma separated IDs of users who may read the audit log",
+		set: func(c *Config, v string) error { c.Auth.Admins = splitList(v); return nil }},
+	{flag: "tracing-exporter", env: "TRACING_EXPORTER", usage: "span exporter: none, stdout, file or otlp",
+		set: func(c *Config, v string) error { c.Tracing.Exporter = v; return nil }},
+	{flag: "tracing-file", env: "TRACING_FILE", usage: "file the file exporter appends spans to",
+		set: func(c *Config, v string) error { c.Tracing.File = v; return nil }},
+	{flag: "tracing-endpoint", env: "TRACING_OTLP_ENDPOINT", usage: "host:port of the OTLP/HTTP collector",
+		set: func(c *Config, v string) error { c.Tracing.Endpoint = v; return nil }},
+	{flag: "tracing-insecure", env: "TRACING_OTLP_INSECURE", usage: "send spans to the collector without TLS", boolean: true,
+		set: func(c *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("expected true or false")
+			}
+			c.Tracing.Insecure = b
+			return nil
+		}},
+}
+
+// configFileEnv names the config file when the -config flag is not given.
+const configFileEnv = "CONFIG_FILE"
+
+// Load builds the configuration from args (without the program name), the
+// environment as seen through lookupEnv and the config file they name, then
+// validates it. Usage and flag errors are written to output; -h yields
+// flag.ErrHelp.
+func Load(args []string, lookupEnv func(string) (string, bool), output io.Writer) (*Config, error) {
+	fs := flag.NewFlagSet("meeting-scheduler", flag.ContinueOnError)
+	fs.SetOutput(output)
+	path := fs.String("config", "", "YAML config file (env "+configFileEnv+")")
+	type flagValue struct {
+		s     *setting
+		value string
+	}
+	var flagged []flagValue
+	for i := range settings {
+		s := &settings[i]
+		if s.flag == "" {
+			continue
+		}
+		usage := fmt.Sprintf("%s (env %s)", s.usage, s.env)
+		record := func(v string) error {
+			flagged = append(flagged, flagValue{s, v})
+			return nil
+		}
+		if s.boolean {
+			fs.BoolFunc(s.flag, usage, record)
+		} else {
+			fs.Func(s.flag, usage, record)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	c := Default()
+	if *path == "" {
+		*path, _ = lookupEnv(configFileEnv)
+	}
+	if *path != "" {
+		if err := c.loadFile(*path); err != nil {
+			return nil, err
+		}
+	}
+	for i := range settings {
+		s := &settings[i]
+		if v, ok := lookupEnv(s.env); ok && v != "" {
+			if err := s.set(c, v); err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %w", s.env, v, err)
+			}
+		}
+	}
+	for _, f := range flagged {
+		if err := f.s.set(c, f.value); err != nil {
+			return nil, fmt.Errorf("invalid -%s %q: %w", f.s.flag, f.value, err)
+		}
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once, each prefixed with its
+// name in the config file.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{field}, args...)...))
+	}
+
+	if _, port, err := net.SplitHostPort(c.Addr); err != nil {
+		fail("addr", "expected host:port, got %q", c.Addr)
+	} else if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		fail("addr", "invalid port %q", port)
+	}
+	if !slices.Contains([]string{ModeDebug, ModeRelease, ModeTest}, c.Mode) {
+		fail("mode", "expected debug, release or test, got %q", c.Mode)
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		fail("log_level", "expected debug, info, warn or error, got %q", c.LogLevel)
+	}
+	if c.SuggestionStep < time.Minute || c.SuggestionStep%time.Minute != 0 {
+		fail("suggestion_step", "must be a positive whole number of minutes, got %s", c.SuggestionStep)
+	}
+	if c.TrashRetention < 0 {
+		fail("trash_retention", "must not be negative, got %s", c.TrashRetention)
+	}
+	if c.DrainDelay < 0 {
+		fail("drain_delay", "must not be negative, got %s", c.DrainDelay)
+	}
+	if c.ShutdownTimeout <= 0 {
+		fail("shutdown_timeout", "must be positive, got %s", c.ShutdownTimeout)
+	}
+
+	switch c.Storage.Backend {
+	case BackendMemory:
+		if c.Storage.DSN != "" {
+			fail("storage.dsn", "the memory backend takes no DSN")
+		}
+	case BackendFile:
+		if c.Storage.DSN == "" {
+			fail("storage.dsn", "the file backend needs the path of its snapshot")
+		}
+		if c.Storage.SnapshotInterval <= 0 {
+			fail("storage.snapshot_interval", "must be positive, got %s", c.Storage.SnapshotInterval)
+		}
+		if c.Storage.JournalCompactSize <= 0 {
+			fail("storage.journal_compact_size", "must be positive, got %d", c.Storage.JournalCompactSize)
+		}
+	default:
+		fail("storage.backend", "expected memory or file, got %q", c.Storage.Backend)
+	}
+
+	for _, origin := range c.CORS.Origins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			fail("cors.origins", "expected * or scheme://host[:port], got %q", origin)
+		}
+	}
+
+	if n := len(c.Auth.MagicLinkSecret); n > 0 && n < minLinkSecret {
+		fail("auth.magic_link_secret", "must be at least %d bytes, got %d", minLinkSecret, n)
+	}
+	for _, id := range c.Auth.Admins {
+		if strings.TrimSpace(id) == "" {
+			fail("auth.admins", "user IDs must not be empty")
+			break
+		}
+	}
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	case tracing.ExporterFile:
+		if c.Tracing.File == "" {
+			fail("tracing.file", "required by the file exporter")
+		}
+	default:
+		fail("tracing.exporter", "expected none, stdout, file or otlp, got %q", c.Tracing.Exporter)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func setDuration(d *time.Duration, v string) error {
+	parsed, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("expected a duration such as 15m")
+	}
+	*d = parsed
+	return nil
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
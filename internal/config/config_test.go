@@ -0,0 +1,129 @@
+package config_test
+
+import (
+	"flag"
+	"io"
+	"meeting-scheduler/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := config.Load(nil, env(nil), io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, `
+addr: ":9000"
+log_level: warn
+suggestion_step: 30m
+storage:
+  backend: memory
+cors:
+  origins: ["https://file.example.com"]
+auth:
+  admins: [alice]
+`)
+	cfg, err := config.Load(
+		[]string{"-config", path, "-addr", ":9100"},
+		env(map[string]string{"LISTEN_ADDR": ":9050", "LOG_LEVEL": "debug", "CORS_ORIGINS": "https://a.example.com, https://b.example.com"}),
+		io.Discard,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ":9100", cfg.Addr, "flags beat the environment")
+	assert.Equal(t, "debug", cfg.LogLevel, "the environment beats the file")
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.CORS.Origins)
+	assert.Equal(t, 30*time.Minute, cfg.SuggestionStep, "the file beats defaults")
+	assert.Equal(t, []string{"alice"}, cfg.Auth.Admins)
+	assert.Equal(t, config.ModeRelease, cfg.Mode)
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	path := writeFile(t, "tracing:\n  exporter: stdout\n")
+	cfg, err := config.Load([]string{"-tracing-insecure"}, env(map[string]string{"CONFIG_FILE": path}), io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "stdout", cfg.Tracing.Exporter)
+	assert.True(t, cfg.Tracing.Insecure)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := config.Load([]string{"-h"}, env(nil), io.Discard)
+	assert.ErrorIs(t, err, flag.ErrHelp)
+
+	_, err = config.Load([]string{"-suggestion-step", "soon"}, env(nil), io.Discard)
+	assert.EqualError(t, err, `invalid -suggestion-step "soon": expected a duration such as 15m`)
+
+	_, err = config.Load(nil, env(map[string]string{"TRACING_OTLP_INSECURE": "maybe"}), io.Discard)
+	assert.EqualError(t, err, `invalid TRACING_OTLP_INSECURE "maybe": expected true or false`)
+
+	_, err = config.Load(nil, env(map[string]string{"CONFIG_FILE": writeFile(t, "adress: x\n")}), io.Discard)
+	assert.ErrorContains(t, err, "field adress not found")
+
+	_, err = config.Load(nil, env(map[string]string{"CONFIG_FILE": filepath.Join(t.TempDir(), "missing.yaml")}), io.Discard)
+	assert.ErrorContains(t, err, "reading config file")
+}
+
+func TestValidate(t *testing.T) {
+	cfg := config.Default()
+	cfg.Addr = "8080"
+	cfg.Mode = "production"
+	cfg.LogLevel = "loud"
+	cfg.SuggestionStep = 90 * time.Second
+	cfg.TrashRetention = -time.Hour
+	cfg.ShutdownTimeout = 0
+	cfg.Storage = config.Storage{Backend: "postgres"}
+	cfg.CORS.Origins = []string{"*", "https://ok.example.com", "app.example.com"}
+	cfg.Auth.MagicLinkSecret = "short"
+	cfg.Tracing.Exporter = "file"
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	for _, want := range []string{
+		`addr: expected host:port, got "8080"`,
+		`mode: expected debug, release or test, got "production"`,
+		`log_level: expected debug, info, warn or error, got "loud"`,
+		`suggestion_step: must be a positive whole number of minutes, got 1m30s`,
+		`trash_retention: must not be negative, got -1h0m0s`,
+		`shutdown_timeout: must be positive, got 0s`,
+		`storage.backend: expected memory or file, got "postgres"`,
+		`cors.origins: expected * or scheme://host[:port], got "app.example.com"`,
+		`auth.magic_link_secret: must be at least 32 bytes, got 5`,
+		`tracing.file: required by the file exporter`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+
+	cfg = config.Default()
+	cfg.Storage.DSN = "file:data.db"
+	assert.ErrorContains(t, cfg.Validate(), "storage.dsn: the memory backend takes no DSN")
+
+	cfg = config.Default()
+	cfg.Storage.Backend = config.BackendFile
+	cfg.Storage.SnapshotInterval = 0
+	cfg.Storage.JournalCompactSize = 0
+	err = cfg.Validate()
+	assert.ErrorContains(t, err, "storage.dsn: the file backend needs the path of its snapshot")
+	assert.ErrorContains(t, err, "storage.snapshot_interval: must be positive, got 0s")
+	assert.ErrorContains(t, err, "storage.journal_compact_size: must be positive, got 0")
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"meeting-scheduler/internal/archive"
+	"meeting-scheduler/internal/model"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxArchiveBytes caps the size of an uploaded archive.
+const maxArchiveBytes = 64 << 20
+
+// ========== Archive Handlers ==========
+
+// @Summary Export all data
+// @Description Download every user, event (including trashed ones), availability entry and API key as one versioned archive. API keys are archived as hashes, so they keep working after an import. Restricted to admins.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.Archive
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/admin/export [get]
+func (h *Handler) exportArchive(c *gin.Context) {
+	a, err := h.svc.ExportArchive(c.Request.Context(), currentUser(c).ID)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusInternalServerError), err)
+		return
+	}
+	filename := "meeting-scheduler-" + a.ExportedAt.Format("20060102T150405Z") + ".json"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+	// The status is already sent, so a failed write can only end the body
+	// early; Decode rejects the truncated archive on import.
+	_ = archive.Write(c.Writer, a)
+}
+
+// @Summary Import data
+// @Description Load an archive made by /api/v1/admin/export. In merge mode records missing here are created and conflicting ones are kept as they are; in replace mode archived records win and events and availability not in the archive are deleted, along with their versions and magic links. Users and API keys are never deleted, and an archived API key that conflicts with one here makes the archive invalid. Events and availability are checked as when they are created. With dry_run nothing is written. Restricted to admins.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param mode query string false "merge (default) or replace"
+// @Param dry_run query bool false "Only report what would change"
+// @Param archive body model.Archive true "Archive to import"
+// @Success 200 {object} model.ImportReport
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Router /api/v1/admin/import [post]
+func (h *Handler) importArchive(c *gin.Context) {
+	mode := c.DefaultQuery("mode", model.ImportMerge)
+	dryRun := false
+	if v := c.Query("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "dry_run must be true or false"})
+			return
+		}
+	}
+	a, err := archive.Decode(http.MaxBytesReader(c.Writer, c.Request.Body, maxArchiveBytes))
+	if err != nil {
+		status := http.StatusBadRequest
+		if tooLarge := new(http.MaxBytesError); errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		respondError(c, status, err)
+		return
+	}
+
+	report, err := h.svc.ImportArchive(c.Request.Context(), currentUser(c).ID, a, mode, dryRun)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, archive.ErrInvalid) {
+			status = http.StatusBadRequest
+		}
+		respondError(c, statusFor(err, status), err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
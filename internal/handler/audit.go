@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"meeting-scheduler/internal/model"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ========== Audit Handlers ==========
+
+// @Summary Get event history
+// @Description List every recorded change to the event and its availability, oldest first
+// @Tags audit
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {array} model.AuditEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/events/{id}/history [get]
+func (h *Handler) getEventHistory(c *gin.Context) {
+	history, err := h.svc.GetEventHistory(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// @Summary Query the audit log
+// @Description Search all recorded mutations. Restricted to admins.
+// @Tags audit
+// @Produce json
+// @Security ApiKeyAuth
+// @Param actor query string false "Acting user or guest ID"
+// @Param entity query string false "Entity type (user, event, availability)"
+// @Param entity_id query string false "Entity ID"
+// @Param event_id query string false "Event ID"
+// @Param since query string false "Earliest timestamp (RFC3339)"
+// @Param until query string false "Latest timestamp (RFC3339)"
+// @Param limit query int false "Return only the most recent N entries"
+// @Success 200 {array} model.AuditEntry
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /api/v1/admin/audit [get]
+func (h *Handler) queryAudit(c *gin.Context) {
+	filter := model.AuditFilter{
+		Actor:    c.Query("actor"),
+		Entity:   c.Query("entity"),
+		EntityID: c.Query("entity_id"),
+		EventID:  c.Query("event_id"),
+	}
+	var err error
+	if filter.Since, err = parseOptionalTime(c.Query("since")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 timestamp"})
+		return
+	}
+	if filter.Until, err = parseOptionalTime(c.Query("until")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "until must be an RFC3339 timestamp"})
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil || filter.Limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a non-negative integer"})
+			return
+		}
+	}
+
+	entries, err := h.svc.QueryAudit(c.Request.Context(), currentUser(c).ID, filter)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusInternalServerError), err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+func parseOptionalTime(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"meeting-scheduler/internal/logging"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const currentUserKey = "auth.user"
+
+// authenticate resolves the API key sent as "Authorization: Bearer <key>" or
+// "X-API-Key: <key>" and stores the matching user on the gin context.
+func (h *Handler) authenticate(c *gin.Context) {
+	key := c.GetHeader("X-API-Key")
+	if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		key = strings.TrimPrefix(auth, "Bearer ")
+	}
+	user, err := h.svc.Authenticate(c.Request.Context(), key)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing or invalid API key"})
+		return
+	}
+	c.Set(currentUserKey, user)
+	logging.AddAttrs(c.Request.Context(), slog.String(logging.KeyUserID, user.ID))
+	c.Next()
+}
+
+// currentUser returns the user set by authenticate.
+func currentUser(c *gin.Context) *model.User {
+	return c.MustGet(currentUserKey).(*model.User)
+}
+
+// statusClientClosedRequest is the non-standard status logged when the
+// client goes away before the response is ready.
+const statusClientClosedRequest = 499
+
+// statusFor maps service authorization, not-found and cancellation errors to
+// their HTTP status and falls back to the given status for everything else.
+func statusFor(err error, fallback int) int {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, service.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return fallback
+	}
+}
+
+// ========== API Key Handlers ==========
+
+// @Summary Get the current user
+// @Description Return the user the request's API key belongs to
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} model.User
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/me [get]
+func (h *Handler) getCurrentUser(c *gin.Context) {
+	c.JSON(http.StatusOK, currentUser(c))
+}
+
+// @Summary Issue an API key
+// @Description Issue an additional API key for the current user. The key is only shown in this response.
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 201 {object} model.IssuedCredential
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/me/api-keys [post]
+func (h *Handler) issueAPIKey(c *gin.Context) {
+	cred, err := h.svc.IssueAPIKey(c.Request.Context(), currentUser(c).ID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusCreated, cred)
+}
+
+// @Summary List API keys
+// @Description List the current user's API keys without their secrets
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Credential
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/me/api-keys [get]
+func (h *Handler) listAPIKeys(c *gin.Context) {
+	keys, err := h.svc.ListAPIKeys(c.Request.Context(), currentUser(c).ID)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusInternalServerError), err)
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// @Summary Revoke an API key
+// @Description Revoke one of the current user's API keys
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Param key_id path string true "API key ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/me/api-keys/{key_id} [delete]
+func (h *Handler) revokeAPIKey(c *gin.Context) {
+	if err := h.svc.RevokeAPIKey(c.Request.Context(), currentUser(c).ID, c.Param("key_id")); err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+}
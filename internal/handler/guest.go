@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"log/slog"
+	"meeting-scheduler/internal/logging"
+	"meeting-scheduler/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const guestLinkKey = "auth.guest_link"
+
+// authenticateGuest accepts a magic link token from the "token" query
+// parameter or the X-Guest-Token header.
+func (h *Handler) authenticateGuest(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		token = c.GetHeader("X-Guest-Token")
+	}
+	link, err := h.svc.AuthenticateGuest(c.Request.Context(), token)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing, expired or revoked link"})
+		return
+	}
+	c.Set(guestLinkKey, link)
+	logging.AddAttrs(c.Request.Context(),
+		slog.String(logging.KeyGuestID, link.GuestID),
+		slog.String(logging.KeyEventID, link.EventID))
+	c.Next()
+}
+
+func currentGuestLink(c *gin.Context) *model.MagicLink {
+	return c.MustGet(guestLinkKey).(*model.MagicLink)
+}
+
+// ========== Magic Link Handlers ==========
+
+// @Summary Create a guest magic link
+// @Description Issue a signed, expiring link for an existing or new guest of the event. Only organizers may create links.
+// @Tags guest
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param request body model.MagicLinkRequest true "Guest to invite"
+// @Success 201 {object} model.IssuedMagicLink
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/links [post]
+func (h *Handler) createMagicLink(c *gin.Context) {
+	var req model.MagicLinkRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	link, err := h.svc.CreateMagicLink(c.Request.Context(), currentUser(c).ID, c.Param("id"), req)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
+		return
+	}
+	c.JSON(http.StatusCreated, link)
+}
+
+// @Summary List guest magic links
+// @Description List the magic links issued for an event. Only organizers may list links.
+// @Tags guest
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {array} model.MagicLink
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/links [get]
+func (h *Handler) listMagicLinks(c *gin.Context) {
+	links, err := h.svc.ListMagicLinks(c.Request.Context(), currentUser(c).ID, c.Param("id"))
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusNotFound), err)
+		return
+	}
+	c.JSON(http.StatusOK, links)
+}
+
+// @Summary Revoke a guest magic link
+// @Description Revoke a magic link before it expires. Only organizers may revoke links.
+// @Tags guest
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param link_id path string true "Magic link ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/links/{link_id} [delete]
+func (h *Handler) revokeMagicLink(c *gin.Context) {
+	if err := h.svc.RevokeMagicLink(c.Request.Context(), currentUser(c).ID, c.Param("id"), c.Param("link_id")); err != nil {
+		respondError(c, statusFor(err, http.StatusNotFound), err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+}
+
+// ========== Guest Handlers ==========
+
+// @Summary Get the guest's event
+// @Description Return the event a magic link was issued for
+// @Tags guest
+// @Produce json
+// @Param token query string true "Magic link token"
+// @Success 200 {object} model.Event
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/guest/event [get]
+func (h *Handler) getGuestEvent(c *gin.Context) {
+	event, err := h.svc.GetEvent(c.Request.Context(), currentGuestLink(c).EventID)
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// @Summary Get the guest's availability
+// @Description Return the availability the guest submitted for the link's event
+// @Tags guest
+// @Produce json
+// @Param token query string true "Magic link token"
+// @Success 200 {object} model.Availability
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/guest/availability [get]
+func (h *Handler) getGuestAvailability(c *gin.Context) {
+	av, err := h.svc.GetGuestAvailability(c.Request.Context(), currentGuestLink(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "availability not found"})
+		return
+	}
+	c.JSON(http.StatusOK, av)
+}
+
+// @Summary Submit the guest's availability
+// @Description Create or replace the guest's availability for the link's event. Event and user IDs come from the link.
+// @Tags guest
+// @Accept json
+// @Produce json
+// @Param token query string true "Magic link token"
+// @Param availability body model.Availability true "Available slots"
+// @Success 200 {object} model.Availability
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/guest/availability [put]
+func (h *Handler) putGuestAvailability(c *gin.Context) {
+	var body model.Availability
+	if err := c.BindJSON(&body); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	av, err := h.svc.SubmitGuestAvailability(c.Request.Context(), currentGuestLink(c), body.Slots)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, av)
+}
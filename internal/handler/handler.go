@@ -1,15 +1,19 @@
 package handler
 
 import (
+	"fmt"
 	"meeting-scheduler/internal/model"
 	"meeting-scheduler/internal/service"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Handler struct {
-	svc *service.SchedulerService
+	svc      *service.SchedulerService
+	draining atomic.Bool
 }
 
 func NewHandler(svc *service.SchedulerService) *Handler {
@@ -20,31 +24,103 @@ func (h *Handler) RegisterRoutes(r *gin.Engine) {
 
 	// Health Check
 	r.GET("/ping", h.healthCheck)
+	r.GET("/healthz", h.liveness)
+	r.GET("/readyz", h.readiness)
 
-	// User routes
-	r.GET("/user/:id", h.getUser)
-	r.GET("/users", h.getAllUsers)
-	r.POST("/user", h.createUser)
+	// API documentation
+	r.GET("/openapi.json", h.openAPI)
+	r.GET("/swagger/*any", h.swaggerUI)
 
-	// Event routes
-	r.GET("/event/:id", h.getEvent)
-	r.POST("/event", h.createEvent)
-	r.PUT("/event", h.updateEvent)
-	r.DELETE("/event/:id", h.deleteEvent)
+	h.registerV1Routes(r.Group("/api/v1"))
+	h.registerLegacyRoutes(r)
+}
+
+func (h *Handler) registerV1Routes(api *gin.RouterGroup) {
+	// Sign-up is the only open write: it returns the new user's first API key.
+	api.POST("/users", h.createUser)
+
+	// Guests authenticate with a magic link token instead of an API key.
+	guest := api.Group("/guest", h.authenticateGuest)
+	guest.GET("/event", h.getGuestEvent)
+	guest.GET("/availability", h.getGuestAvailability)
+	guest.PUT("/availability", h.putGuestAvailability)
+
+	// Everything below requires an API key.
+	authed := api.Group("", h.authenticate)
+
+	// Current user and API keys
+	authed.GET("/me", h.getCurrentUser)
+	authed.GET("/me/api-keys", h.listAPIKeys)
+	authed.POST("/me/api-keys", h.issueAPIKey)
+	authed.DELETE("/me/api-keys/:key_id", h.revokeAPIKey)
 
-	// Availability routes
-	r.GET("/event/:id/availability/:user_id", h.getAvailability)
-	r.POST("/event/availability", h.addAvailability)
-	r.PUT("/event/availability", h.updateAvailability)
-	r.DELETE("/event/:id/availability/:user_id", h.removeAvailability)
+	// Users
+	authed.GET("/users", h.getAllUsers)
+	authed.GET("/users/:id", h.getUser)
+
+	// Events
+	authed.GET("/events", h.listEvents)
+	authed.POST("/events", h.createEvent)
+	authed.GET("/events/:id", h.getEvent)
+	authed.PUT("/events/:id", h.updateEvent)
+	authed.DELETE("/events/:id", h.deleteEvent)
+	authed.POST("/events/:id/restore", h.undeleteEvent)
+	authed.POST("/events/:id/finalize", h.finalizeEvent)
+	authed.GET("/trash", h.listTrash)
+	authed.POST("/schedules", h.scheduleBatch)
+
+	// Guest magic links
+	authed.GET("/events/:id/links", h.listMagicLinks)
+	authed.POST("/events/:id/links", h.createMagicLink)
+	authed.DELETE("/events/:id/links/:link_id", h.revokeMagicLink)
+
+	// Availability
+	authed.GET("/events/:id/availability/:user_id", h.getAvailability)
+	authed.PUT("/events/:id/availability/:user_id", h.putAvailability)
+	authed.DELETE("/events/:id/availability/:user_id", h.removeAvailability)
+	authed.POST("/events/:id/decline", h.declineEvent)
+	authed.GET("/events/:id/responses", h.getResponseSummary)
 
 	// Suggestions
-	r.GET("/event/:id/suggestions", h.suggestSlots)
+	authed.GET("/events/:id/suggestions", h.suggestSlots)
+	authed.GET("/events/:id/suggestions/explain", h.explainSuggestion)
+	authed.GET("/events/:id/suggestions/split", h.suggestSplitSessions)
+
+	// Audit
+	authed.GET("/events/:id/history", h.getEventHistory)
+	authed.GET("/admin/audit", h.queryAudit)
+
+	// Versions
+	authed.GET("/events/:id/versions", h.listEventVersions)
+	authed.GET("/events/:id/versions/diff", h.diffEventVersions)
+	authed.GET("/events/:id/versions/:version", h.getEventVersion)
+	authed.POST("/events/:id/versions/:version/restore", h.restoreEventVersion)
+
+	// Backup and restore
+	authed.GET("/admin/export", h.exportArchive)
+	authed.POST("/admin/import", h.importArchive)
 }
 
 // ========== Health Check ==========
 
+// Drain makes /ping and /readyz report the server as unavailable, so load
+// balancers stop sending it new requests while it shuts down.
+func (h *Handler) Drain() {
+	h.draining.Store(true)
+}
+
+// @Summary Ping
+// @Description Answers 503 while the server shuts down
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /ping [get]
 func (h *Handler) healthCheck(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"status": "ok",
 	})
@@ -55,15 +131,18 @@ func (h *Handler) healthCheck(c *gin.Context) {
 // @Summary Get user by ID
 // @Description Get user details by user ID
 // @Tags user
+// @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "User ID"
 // @Success 200 {object} model.User
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
-// @Router /user/{id} [get]
+// @Router /api/v1/users/{id} [get]
 func (h *Handler) getUser(c *gin.Context) {
 	userId := c.Param("id")
-	userInfo, err := h.svc.GetUser(userId)
+	userInfo, err := h.svc.GetUser(c.Request.Context(), userId)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 	c.JSON(http.StatusOK, userInfo)
@@ -73,39 +152,42 @@ func (h *Handler) getUser(c *gin.Context) {
 // @Description Retrieve a list of all registered users
 // @Tags user
 // @Produce json
+// @Security ApiKeyAuth
 // @Success 200 {array} model.User
+// @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /users [get]
+// @Router /api/v1/users [get]
 func (h *Handler) getAllUsers(c *gin.Context) {
-	allUsersInfo, err := h.svc.GetAllUsers()
+	allUsersInfo, err := h.svc.GetAllUsers(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, err)
 		return
 	}
 	c.JSON(http.StatusOK, allUsersInfo)
 }
 
 // @Summary Create a new user
-// @Description Register a new user with name and ID
+// @Description Register a new user with name and ID. The response carries the user's first API key, which is not shown again.
 // @Tags user
 // @Accept json
 // @Produce json
 // @Param user body model.User true "User to create"
-// @Success 201 {object} model.User
+// @Success 201 {object} model.Registration
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /user [post]
+// @Router /api/v1/users [post]
 func (h *Handler) createUser(c *gin.Context) {
 	var u model.User
 	if err := c.BindJSON(&u); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body: " + err.Error()})
 		return
 	}
-	if err := h.svc.CreateUser(&u); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not create user: " + err.Error()})
+	registration, err := h.svc.RegisterUser(c.Request.Context(), &u)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, fmt.Errorf("could not create user: %w", err))
 		return
 	}
-	c.JSON(http.StatusCreated, u)
+	c.JSON(http.StatusCreated, registration)
 }
 
 // ========== Event Handlers ==========
@@ -113,60 +195,95 @@ func (h *Handler) createUser(c *gin.Context) {
 // @Description Retrieve event details by event ID
 // @Tags event
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "Event ID"
 // @Success 200 {object} model.Event
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
-// @Router /event/{id} [get]
+// @Router /api/v1/events/{id} [get]
 func (h *Handler) getEvent(c *gin.Context) {
 	eventId := c.Param("id")
-	event, err := h.svc.GetEvent(eventId)
+	event, err := h.svc.GetEvent(c.Request.Context(), eventId)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, http.StatusNotFound, err)
 		return
 	}
 	c.JSON(http.StatusOK, event)
 }
 
+// @Summary List events
+// @Description List the events the caller organizes or takes part in, ordered by ID
+// @Tags event
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Event
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/events [get]
+func (h *Handler) listEvents(c *gin.Context) {
+	events, err := h.svc.ListEvents(c.Request.Context(), currentUser(c).ID)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusInternalServerError), err)
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
 // @Summary Create a new event
-// @Description Create an event with title, duration, and time slots
+// @Description Create an event with title, duration, and time slots. The caller becomes the event's organizer.
 // @Tags event
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param event body model.Event true "Event to create"
 // @Success 201 {object} model.Event
 // @Failure 400 {object} map[string]string
-// @Router /event [post]
+// @Failure 401 {object} map[string]string
+// @Router /api/v1/events [post]
 func (h *Handler) createEvent(c *gin.Context) {
 	var e model.Event
 	if err := c.BindJSON(&e); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
-	if err := h.svc.CreateEvent(&e); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.svc.CreateEvent(c.Request.Context(), currentUser(c).ID, &e); err != nil {
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
 	c.JSON(http.StatusCreated, e)
 }
 
 // @Summary Update an event
-// @Description Update event details (slots, title, duration, etc.)
+// @Description Update event details (slots, title, duration, etc.). Only organizers may update an event.
 // @Tags event
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
 // @Param event body model.Event true "Event to update"
 // @Success 200 {object} model.Event
 // @Failure 400 {object} map[string]string
-// @Router /event [put]
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id} [put]
 func (h *Handler) updateEvent(c *gin.Context) {
 	var e model.Event
 	if err := c.BindJSON(&e); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
+	// The legacy route takes the ID from the body only.
+	if id := c.Param("id"); id != "" {
+		if e.ID != "" && e.ID != id {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "event ID in the body does not match the path"})
+			return
+		}
+		e.ID = id
+	}
 
-	if err := h.svc.UpdateEvent(&e); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.svc.UpdateEvent(c.Request.Context(), currentUser(c).ID, &e); err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
 		return
 	}
 
@@ -174,45 +291,133 @@ func (h *Handler) updateEvent(c *gin.Context) {
 }
 
 // @Summary Delete an event
-// @Description Delete event by ID
+// @Description Move the event to the trash. It can be restored until the trash retention period has passed. Only organizers may delete an event.
 // @Tags event
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "Event ID"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
-// @Router /event/{id} [delete]
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id} [delete]
 func (h *Handler) deleteEvent(c *gin.Context) {
 	id := c.Param("id")
 
-	if err := h.svc.DeleteEvent(id); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.svc.DeleteEvent(c.Request.Context(), currentUser(c).ID, id); err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
 
-// ========== Availability Handlers ==========
+// @Summary Restore a deleted event
+// @Description Take an event back out of the trash, together with its availability. Only organizers may restore an event.
+// @Tags event
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} model.Event
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/restore [post]
+func (h *Handler) undeleteEvent(c *gin.Context) {
+	event, err := h.svc.UndeleteEvent(c.Request.Context(), currentUser(c).ID, c.Param("id"))
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusNotFound), err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
 
-// @Summary Add user availability
-// @Description Add time slots a user is available for an event
-// @Tags availability
+// @Summary List deleted events
+// @Description List the trashed events the caller organizes, most recently deleted first
+// @Tags event
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {array} model.Event
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/trash [get]
+func (h *Handler) listTrash(c *gin.Context) {
+	events, err := h.svc.ListTrash(c.Request.Context(), currentUser(c).ID)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusInternalServerError), err)
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// @Summary Finalize an event
+// @Description Fix the event to one session, or to several sessions for events with a split configuration. Only organizers may finalize an event.
+// @Tags event
 // @Accept json
 // @Produce json
-// @Param availability body model.Availability true "Availability to add"
-// @Success 201 {object} model.Availability
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param request body model.FinalizeRequest true "Chosen sessions"
+// @Success 200 {object} model.Event
 // @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /event/availability [post]
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/finalize [post]
+func (h *Handler) finalizeEvent(c *gin.Context) {
+	var req model.FinalizeRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	event, err := h.svc.FinalizeEvent(c.Request.Context(), currentUser(c).ID, c.Param("id"), req.Sessions)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// @Summary Schedule several events together
+// @Description Assign each event a window from its slots so that events sharing participants do not overlap, maximizing total attendance
+// @Tags event
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body model.BatchScheduleRequest true "Events to schedule"
+// @Success 200 {object} model.BatchSchedule
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/schedules [post]
+func (h *Handler) scheduleBatch(c *gin.Context) {
+	var req model.BatchScheduleRequest
+	if err := c.BindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	schedule, err := h.svc.ScheduleBatch(c.Request.Context(), req.EventIDs)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// ========== Availability Handlers ==========
+
+// addAvailability serves the legacy POST /event/availability, which names
+// the event in the body and fails if the user has already responded.
 func (h *Handler) addAvailability(c *gin.Context) {
 	var av model.Availability
 	if err := c.BindJSON(&av); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
-	err := h.svc.AddAvailability(av)
+	err := h.svc.AddAvailability(c.Request.Context(), currentUser(c).ID, &av)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, statusFor(err, http.StatusInternalServerError), err)
 		return
 	}
 	c.JSON(http.StatusCreated, av)
@@ -222,15 +427,17 @@ func (h *Handler) addAvailability(c *gin.Context) {
 // @Description Get a user's availability for a given event
 // @Tags availability
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "Event ID"
 // @Param user_id path string true "User ID"
 // @Success 200 {object} model.Availability
+// @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
-// @Router /event/{id}/availability/{user_id} [get]
+// @Router /api/v1/events/{id}/availability/{user_id} [get]
 func (h *Handler) getAvailability(c *gin.Context) {
 	eid := c.Param("id")
 	uid := c.Param("user_id")
-	av, err := h.svc.GetAvailability(eid, uid)
+	av, err := h.svc.GetAvailability(c.Request.Context(), eid, uid)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "availability not found"})
 		return
@@ -238,25 +445,57 @@ func (h *Handler) getAvailability(c *gin.Context) {
 	c.JSON(http.StatusOK, av)
 }
 
-// @Summary Update user availability
-// @Description Update time slots a user is available for an event
+// @Summary Set user availability
+// @Description Create or replace the time slots a user is available for an event. Users may only set their own availability.
 // @Tags availability
 // @Accept json
 // @Produce json
-// @Param availability body model.Availability true "Availability to update"
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param user_id path string true "User ID"
+// @Param availability body model.Availability true "Available slots"
 // @Success 200 {object} model.Availability
+// @Success 201 {object} model.Availability
 // @Failure 400 {object} map[string]string
-// @Failure 500 {object} map[string]string
-// @Router /event/availability [put]
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/availability/{user_id} [put]
+func (h *Handler) putAvailability(c *gin.Context) {
+	var av model.Availability
+	if err := c.BindJSON(&av); err != nil {
+		respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	eid, uid := c.Param("id"), c.Param("user_id")
+	if (av.EventID != "" && av.EventID != eid) || (av.UserID != "" && av.UserID != uid) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "event or user ID in the body does not match the path"})
+		return
+	}
+	av.EventID, av.UserID = eid, uid
+	created, err := h.svc.SetAvailability(c.Request.Context(), currentUser(c).ID, &av)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, av)
+}
+
+// updateAvailability serves the legacy PUT /event/availability, which names
+// the event in the body and fails if the user has not responded yet.
 func (h *Handler) updateAvailability(c *gin.Context) {
 	var av model.Availability
 	if err := c.BindJSON(&av); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, http.StatusBadRequest, err)
 		return
 	}
-	err := h.svc.UpdateAvailability(av)
+	err := h.svc.UpdateAvailability(c.Request.Context(), currentUser(c).ID, &av)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, statusFor(err, http.StatusInternalServerError), err)
 		return
 	}
 	c.JSON(http.StatusOK, av)
@@ -266,32 +505,130 @@ func (h *Handler) updateAvailability(c *gin.Context) {
 // @Description Remove a user's availability for a specific event
 // @Tags availability
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "Event ID"
 // @Param user_id path string true "User ID"
 // @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
-// @Router /event/{id}/availability/{user_id} [delete]
+// @Router /api/v1/events/{id}/availability/{user_id} [delete]
 func (h *Handler) removeAvailability(c *gin.Context) {
 	eid := c.Param("id")
 	uid := c.Param("user_id")
-	if err := h.svc.DeleteAvailability(eid, uid); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.svc.DeleteAvailability(c.Request.Context(), currentUser(c).ID, eid, uid); err != nil {
+		respondError(c, statusFor(err, http.StatusInternalServerError), err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
 
+// @Summary Decline an event
+// @Description Record that the current user will not attend, replacing any submitted availability
+// @Tags availability
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} model.Availability
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/decline [post]
+func (h *Handler) declineEvent(c *gin.Context) {
+	av, err := h.svc.DeclineEvent(c.Request.Context(), c.Param("id"), currentUser(c).ID)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
+		return
+	}
+	c.JSON(http.StatusOK, av)
+}
+
+// @Summary Get event response summary
+// @Description List which participants have responded, declined or are still pending, with last-updated timestamps
+// @Tags availability
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} model.ResponseSummary
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/responses [get]
+func (h *Handler) getResponseSummary(c *gin.Context) {
+	summary, err := h.svc.GetResponseSummary(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
 // ========== Suggestion Handler ==========
 
 // @Summary Suggest meeting slots
-// @Description Suggest best time slots for a meeting based on availability
+// @Description Suggest best time slots for a meeting based on availability. When no window meets the event's quorum the result is marked not viable and explains the best achievable attendance.
 // @Tags suggestion
 // @Produce json
+// @Security ApiKeyAuth
 // @Param id path string true "Event ID"
-// @Success 200 {object} map[string][]model.SlotSuggestion
-// @Router /event/{id}/suggestions [get]
+// @Success 200 {object} model.SuggestionResult
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/suggestions [get]
 func (h *Handler) suggestSlots(c *gin.Context) {
 	id := c.Param("id")
-	slots, _ := h.svc.SuggestSlots(id)
-	c.JSON(http.StatusOK, gin.H{"suggested_slots": slots})
+	result, err := h.svc.SuggestSlots(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusNotFound), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Explain a suggestion window
+// @Description Report each participant's status for the window starting at the given time, with the window's score and rank relative to the winning window
+// @Tags suggestion
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param start query string true "Window start (RFC3339)"
+// @Success 200 {object} model.WindowExplanation
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/suggestions/explain [get]
+func (h *Handler) explainSuggestion(c *gin.Context) {
+	id := c.Param("id")
+	start, err := time.Parse(time.RFC3339, c.Query("start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start must be an RFC3339 timestamp"})
+		return
+	}
+	explanation, err := h.svc.ExplainWindow(c.Request.Context(), id, start)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
+		return
+	}
+	c.JSON(http.StatusOK, explanation)
+}
+
+// @Summary Suggest split sessions
+// @Description Suggest sets of shorter sessions covering the event's duration for events with a split configuration
+// @Tags suggestion
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} model.SplitSuggestionResult
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/suggestions/split [get]
+func (h *Handler) suggestSplitSessions(c *gin.Context) {
+	result, err := h.svc.SuggestSplitSessions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
 }
@@ -0,0 +1,516 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"meeting-scheduler/internal/handler"
+	"meeting-scheduler/internal/logging"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	slotStart = time.Date(2025, time.May, 20, 9, 0, 0, 0, time.UTC)
+	slotEnd   = slotStart.Add(2 * time.Hour)
+)
+
+// fixture is a server with event e1 organized by "org", co-organized by "co"
+// and attended by "org" and "p1". p1 has already submitted availability;
+// "out" is a registered user with no relation to e1 and "admin" may read the
+// audit log.
+type fixture struct {
+	router *gin.Engine
+	keys   map[string]string
+	logs   *bytes.Buffer
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	svc := service.NewSchedulerService(
+		repository.NewInMemoryUserRepository(),
+		repository.NewInMemoryEventRepository(),
+		repository.NewInMemoryAvailabilityRepository(),
+		service.WithAdmins("admin"),
+	)
+	f := &fixture{router: gin.New(), keys: map[string]string{}, logs: &bytes.Buffer{}}
+	logger := logging.New(f.logs, slog.LevelInfo)
+	f.router.Use(handler.RequestLogger(logger), handler.Recoverer(logger))
+	handler.NewHandler(svc).RegisterRoutes(f.router)
+
+	for _, id := range []string{"org", "co", "p1", "out", "admin"} {
+		registration, err := svc.RegisterUser(t.Context(), &model.User{ID: id, Name: id})
+		require.NoError(t, err)
+		f.keys[id] = registration.Credential.Key
+	}
+	require.NoError(t, svc.CreateEvent(t.Context(), "org", &model.Event{
+		ID: "e1", Title: "Planning", DurationMin: 60,
+		Participants: []string{"org", "p1"},
+		CoOrganizers: []string{"co"},
+		Slots:        []model.Slot{{Start: slotStart, End: slotEnd}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "p1", &model.Availability{
+		EventID: "e1", Slots: []model.Slot{{Start: slotStart, End: slotEnd}},
+	}))
+	return f
+}
+
+func (f *fixture) do(method, path, actor string, body any) *httptest.ResponseRecorder {
+	var payload io.Reader
+	if body != nil {
+		b, _ := json.Marshal(body)
+		payload = bytes.NewReader(b)
+	}
+	req := httptest.NewRequest(method, path, payload)
+	req.Header.Set("Content-Type", "application/json")
+	if actor != "" {
+		req.Header.Set("Authorization", "Bearer "+f.keys[actor])
+	}
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRoutes_Authorization(t *testing.T) {
+	event := func(title string) model.Event {
+		return model.Event{
+			ID: "e1", Title: title, DurationMin: 60,
+			Participants: []string{"org", "p1"},
+			Slots:        []model.Slot{{Start: slotStart, End: slotEnd}},
+		}
+	}
+	newEvent := model.Event{ID: "e2", DurationMin: 30, Participants: []string{"p1"}}
+	ownAvailability := model.Availability{EventID: "e1", Slots: []model.Slot{{Start: slotStart, End: slotEnd}}}
+	finalize := model.FinalizeRequest{Sessions: []model.Slot{{Start: slotStart, End: slotStart.Add(time.Hour)}}}
+	explain := "/api/v1/events/e1/suggestions/explain?start=" + slotStart.Format(time.RFC3339)
+	archive := model.Archive{Kind: model.ArchiveKind, Version: model.ArchiveVersion}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		actor  string
+		body   any
+		want   int
+	}{
+		{"unknown route", http.MethodGet, "/nope", "", nil, http.StatusNotFound},
+		{"ping is public", http.MethodGet, "/ping", "", nil, http.StatusOK},
+		{"openapi document is public", http.MethodGet, "/openapi.json", "", nil, http.StatusOK},
+		{"swagger ui is public", http.MethodGet, "/swagger/index.html", "", nil, http.StatusOK},
+		{"swagger ui directory", http.MethodGet, "/swagger/", "", nil, http.StatusMovedPermanently},
+		{"sign-up is public", http.MethodPost, "/api/v1/users", "", model.User{ID: "new", Name: "New"}, http.StatusCreated},
+
+		{"me requires a key", http.MethodGet, "/api/v1/me", "", nil, http.StatusUnauthorized},
+		{"me", http.MethodGet, "/api/v1/me", "p1", nil, http.StatusOK},
+		{"list api keys", http.MethodGet, "/api/v1/me/api-keys", "p1", nil, http.StatusOK},
+		{"issue api key", http.MethodPost, "/api/v1/me/api-keys", "p1", nil, http.StatusCreated},
+		{"revoke unknown api key", http.MethodDelete, "/api/v1/me/api-keys/unknown", "p1", nil, http.StatusNotFound},
+
+		{"get user requires a key", http.MethodGet, "/api/v1/users/p1", "", nil, http.StatusUnauthorized},
+		{"get user", http.MethodGet, "/api/v1/users/p1", "out", nil, http.StatusOK},
+		{"list users", http.MethodGet, "/api/v1/users", "out", nil, http.StatusOK},
+
+		{"get event", http.MethodGet, "/api/v1/events/e1", "out", nil, http.StatusOK},
+		{"create event requires a key", http.MethodPost, "/api/v1/events", "", newEvent, http.StatusUnauthorized},
+		{"create event", http.MethodPost, "/api/v1/events", "p1", newEvent, http.StatusCreated},
+		{"update event as organizer", http.MethodPut, "/api/v1/events/e1", "org", event("Renamed"), http.StatusOK},
+		{"update event as co-organizer", http.MethodPut, "/api/v1/events/e1", "co", event("Renamed"), http.StatusOK},
+		{"update event as participant", http.MethodPut, "/api/v1/events/e1", "p1", event("Renamed"), http.StatusForbidden},
+		{"update event as outsider", http.MethodPut, "/api/v1/events/e1", "out", event("Renamed"), http.StatusForbidden},
+		{"update event with another ID in the body", http.MethodPut, "/api/v1/events/e2", "org", event("Renamed"), http.StatusBadRequest},
+		{"delete event as organizer", http.MethodDelete, "/api/v1/events/e1", "org", nil, http.StatusOK},
+		{"delete event as co-organizer", http.MethodDelete, "/api/v1/events/e1", "co", nil, http.StatusOK},
+		{"delete event as participant", http.MethodDelete, "/api/v1/events/e1", "p1", nil, http.StatusForbidden},
+		{"delete event as outsider", http.MethodDelete, "/api/v1/events/e1", "out", nil, http.StatusForbidden},
+		{"restore event that is not deleted", http.MethodPost, "/api/v1/events/e1/restore", "org", nil, http.StatusNotFound},
+		{"list events requires a key", http.MethodGet, "/api/v1/events", "", nil, http.StatusUnauthorized},
+		{"list events", http.MethodGet, "/api/v1/events", "out", nil, http.StatusOK},
+		{"list trash", http.MethodGet, "/api/v1/trash", "org", nil, http.StatusOK},
+		{"finalize event as organizer", http.MethodPost, "/api/v1/events/e1/finalize", "org", finalize, http.StatusOK},
+		{"finalize event as participant", http.MethodPost, "/api/v1/events/e1/finalize", "p1", finalize, http.StatusForbidden},
+		{"schedule batch", http.MethodPost, "/api/v1/schedules", "p1", model.BatchScheduleRequest{EventIDs: []string{"e1"}}, http.StatusOK},
+
+		{"get availability", http.MethodGet, "/api/v1/events/e1/availability/p1", "org", nil, http.StatusOK},
+		{"set own availability", http.MethodPut, "/api/v1/events/e1/availability/org", "org", ownAvailability, http.StatusCreated},
+		{"replace own availability", http.MethodPut, "/api/v1/events/e1/availability/p1", "p1", ownAvailability, http.StatusOK},
+		{"set availability as outsider", http.MethodPut, "/api/v1/events/e1/availability/out", "out", ownAvailability, http.StatusForbidden},
+		{"set availability for someone else", http.MethodPut, "/api/v1/events/e1/availability/p1", "org", ownAvailability, http.StatusForbidden},
+		{"set availability with another event in the body", http.MethodPut, "/api/v1/events/e1/availability/p1", "p1", model.Availability{EventID: "e2"}, http.StatusBadRequest},
+		{"set availability with another user in the body", http.MethodPut, "/api/v1/events/e1/availability/p1", "p1", model.Availability{UserID: "org"}, http.StatusBadRequest},
+		{"remove own availability", http.MethodDelete, "/api/v1/events/e1/availability/p1", "p1", nil, http.StatusOK},
+		{"remove availability for someone else", http.MethodDelete, "/api/v1/events/e1/availability/p1", "org", nil, http.StatusForbidden},
+		{"decline as participant", http.MethodPost, "/api/v1/events/e1/decline", "p1", nil, http.StatusOK},
+		{"decline as outsider", http.MethodPost, "/api/v1/events/e1/decline", "out", nil, http.StatusForbidden},
+		{"response summary", http.MethodGet, "/api/v1/events/e1/responses", "p1", nil, http.StatusOK},
+
+		{"create magic link as organizer", http.MethodPost, "/api/v1/events/e1/links", "org", model.MagicLinkRequest{Name: "Guest"}, http.StatusCreated},
+		{"create magic link as participant", http.MethodPost, "/api/v1/events/e1/links", "p1", model.MagicLinkRequest{Name: "Guest"}, http.StatusForbidden},
+		{"list magic links as organizer", http.MethodGet, "/api/v1/events/e1/links", "org", nil, http.StatusOK},
+		{"list magic links as participant", http.MethodGet, "/api/v1/events/e1/links", "p1", nil, http.StatusForbidden},
+		{"revoke unknown magic link", http.MethodDelete, "/api/v1/events/e1/links/unknown", "org", nil, http.StatusNotFound},
+		{"guest event requires a token", http.MethodGet, "/api/v1/guest/event", "", nil, http.StatusUnauthorized},
+		{"guest event rejects api keys", http.MethodGet, "/api/v1/guest/event", "p1", nil, http.StatusUnauthorized},
+		{"guest availability requires a token", http.MethodPut, "/api/v1/guest/availability", "", ownAvailability, http.StatusUnauthorized},
+
+		{"event history", http.MethodGet, "/api/v1/events/e1/history", "p1", nil, http.StatusOK},
+		{"audit log as admin", http.MethodGet, "/api/v1/admin/audit?entity=event&limit=5", "admin", nil, http.StatusOK},
+		{"audit log as non-admin", http.MethodGet, "/api/v1/admin/audit", "org", nil, http.StatusForbidden},
+		{"audit log with bad filter", http.MethodGet, "/api/v1/admin/audit?since=yesterday", "admin", nil, http.StatusBadRequest},
+		{"export as admin", http.MethodGet, "/api/v1/admin/export", "admin", nil, http.StatusOK},
+		{"export as non-admin", http.MethodGet, "/api/v1/admin/export", "org", nil, http.StatusForbidden},
+		{"import as non-admin", http.MethodPost, "/api/v1/admin/import", "org", archive, http.StatusForbidden},
+		{"import dry run", http.MethodPost, "/api/v1/admin/import?mode=replace&dry_run=true", "admin", archive, http.StatusOK},
+		{"import with unknown mode", http.MethodPost, "/api/v1/admin/import?mode=overwrite", "admin", archive, http.StatusBadRequest},
+		{"import something else", http.MethodPost, "/api/v1/admin/import", "admin", gin.H{"kind": "backup"}, http.StatusBadRequest},
+
+		{"list versions", http.MethodGet, "/api/v1/events/e1/versions", "p1", nil, http.StatusOK},
+		{"get version", http.MethodGet, "/api/v1/events/e1/versions/1", "p1", nil, http.StatusOK},
+		{"get unknown version", http.MethodGet, "/api/v1/events/e1/versions/9", "p1", nil, http.StatusNotFound},
+		{"diff versions", http.MethodGet, "/api/v1/events/e1/versions/diff?from=1&to=2", "p1", nil, http.StatusOK},
+		{"diff versions without bounds", http.MethodGet, "/api/v1/events/e1/versions/diff", "p1", nil, http.StatusBadRequest},
+		{"restore version as organizer", http.MethodPost, "/api/v1/events/e1/versions/1/restore", "org", nil, http.StatusOK},
+		{"restore version as participant", http.MethodPost, "/api/v1/events/e1/versions/1/restore", "p1", nil, http.StatusForbidden},
+
+		{"suggestions", http.MethodGet, "/api/v1/events/e1/suggestions", "p1", nil, http.StatusOK},
+		{"suggestions for unknown event", http.MethodGet, "/api/v1/events/nope/suggestions", "p1", nil, http.StatusNotFound},
+		{"explain suggestion", http.MethodGet, explain, "p1", nil, http.StatusOK},
+		{"explain suggestion for unknown event", http.MethodGet, "/api/v1/events/nope/suggestions/explain?start=" + slotStart.Format(time.RFC3339), "p1", nil, http.StatusNotFound},
+		{"split suggestions without split config", http.MethodGet, "/api/v1/events/e1/suggestions/split", "p1", nil, http.StatusBadRequest},
+	}
+
+	spec := loadSpec(t, newFixture(t))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			w := f.do(tt.method, tt.path, tt.actor, tt.body)
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+			assert.Empty(t, w.Header().Get("Deprecation"))
+			assertDocumented(t, spec, tt.method, tt.path, w)
+		})
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	f := newFixture(t)
+
+	w := f.do(http.MethodGet, "/api/v1/admin/export", "admin", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+	var exported model.Archive
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &exported))
+	require.Len(t, exported.Events, 1)
+	require.Len(t, exported.Availability, 1)
+	assert.Len(t, exported.Users, 5)
+
+	// Change e1 and add e2, then see what merging and replacing the export
+	// would do.
+	renamed := model.Event{
+		ID: "e1", Title: "Renamed", DurationMin: 60,
+		Participants: []string{"org", "p1"}, CoOrganizers: []string{"co"},
+		Slots: []model.Slot{{Start: slotStart, End: slotEnd}},
+	}
+	require.Equal(t, http.StatusOK, f.do(http.MethodPut, "/api/v1/events/e1", "org", renamed).Code)
+	require.Equal(t, http.StatusCreated, f.do(http.MethodPost, "/api/v1/events", "org", model.Event{ID: "e2", DurationMin: 30, Participants: []string{"p1"}}).Code)
+
+	importArchive := func(query string) model.ImportReport {
+		t.Helper()
+		w := f.do(http.MethodPost, "/api/v1/admin/import"+query, "admin", exported)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var report model.ImportReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return report
+	}
+	getTitle := func(id string) string {
+		var e model.Event
+		require.NoError(t, json.Unmarshal(f.do(http.MethodGet, "/api/v1/events/"+id, "org", nil).Body.Bytes(), &e))
+		return e.Title
+	}
+
+	report := importArchive("")
+	assert.Equal(t, model.ImportCounts{Kept: 1}, report.Events)
+	assert.Equal(t, []model.ImportConflict{{Entity: model.EntityEvent, ID: "e1", Resolution: "kept"}}, report.Conflicts)
+	assert.Equal(t, "Renamed", getTitle("e1"))
+
+	report = importArchive("?mode=replace&dry_run=1")
+	assert.True(t, report.DryRun)
+	assert.Equal(t, model.ImportCounts{Updated: 1, Deleted: 1}, report.Events)
+	assert.Equal(t, "Renamed", getTitle("e1"))
+
+	report = importArchive("?mode=replace")
+	assert.Equal(t, model.ImportCounts{Updated: 1, Deleted: 1}, report.Events)
+	assert.Equal(t, "Planning", getTitle("e1"))
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodGet, "/api/v1/events/e2", "org", nil).Code)
+
+	w = f.do(http.MethodGet, "/api/v1/admin/audit?entity=archive", "admin", nil)
+	var entries []model.AuditEntry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	assert.Len(t, entries, 2, "dry runs are not audited")
+}
+
+func TestUpdateEvent_KeepsOrganizer(t *testing.T) {
+	f := newFixture(t)
+
+	update := model.Event{
+		ID: "e1", DurationMin: 60, Organizer: "co",
+		Participants: []string{"org", "p1"}, CoOrganizers: []string{"co"},
+	}
+	w := f.do(http.MethodPut, "/api/v1/events/e1", "co", update)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = f.do(http.MethodGet, "/api/v1/events/e1", "p1", nil)
+	var got model.Event
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, "org", got.Organizer)
+}
+
+func TestGuestMagicLinkFlow(t *testing.T) {
+	f := newFixture(t)
+
+	w := f.do(http.MethodPost, "/api/v1/events/e1/links", "org", model.MagicLinkRequest{Name: "Visitor", TTLMin: 60})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var link model.IssuedMagicLink
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &link))
+	guestPath := func(p string) string { return p + "?token=" + link.Token }
+
+	w = f.do(http.MethodGet, guestPath("/api/v1/guest/event"), "", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var event model.Event
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &event))
+	require.Len(t, event.Guests, 1)
+	assert.Equal(t, link.GuestID, event.Guests[0].ID)
+
+	// The event and user IDs in the body are ignored in favour of the link.
+	body := model.Availability{EventID: "other", UserID: "p1", Slots: []model.Slot{{Start: slotStart, End: slotEnd}}}
+	w = f.do(http.MethodPut, guestPath("/api/v1/guest/availability"), "", body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var av model.Availability
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &av))
+	assert.Equal(t, "e1", av.EventID)
+	assert.Equal(t, link.GuestID, av.UserID)
+
+	w = f.do(http.MethodGet, "/api/v1/events/e1/responses", "p1", nil)
+	assert.Contains(t, w.Body.String(), link.GuestID)
+
+	w = f.do(http.MethodDelete, "/api/v1/events/e1/links/"+link.ID, "org", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = f.do(http.MethodGet, guestPath("/api/v1/guest/availability"), "", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestListEvents(t *testing.T) {
+	f := newFixture(t)
+	for actor, want := range map[string]int{"org": 1, "co": 1, "p1": 1, "out": 0} {
+		w := f.do(http.MethodGet, "/api/v1/events", actor, nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var events []model.Event
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &events))
+		assert.Len(t, events, want, actor)
+	}
+}
+
+func TestDeleteAndRestoreEvent(t *testing.T) {
+	f := newFixture(t)
+
+	w := f.do(http.MethodDelete, "/api/v1/events/e1", "org", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = f.do(http.MethodGet, "/api/v1/events/e1", "p1", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = f.do(http.MethodGet, "/api/v1/trash", "co", nil)
+	var trash []model.Event
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trash))
+	require.Len(t, trash, 1)
+	assert.Equal(t, "e1", trash[0].ID)
+
+	w = f.do(http.MethodPost, "/api/v1/events/e1/restore", "p1", nil)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = f.do(http.MethodPost, "/api/v1/events/e1/restore", "co", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = f.do(http.MethodGet, "/api/v1/events/e1/availability/p1", "org", nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestRequestLogger_CorrelatesErrors(t *testing.T) {
+	f := newFixture(t)
+	f.logs.Reset()
+
+	body := model.Availability{Slots: []model.Slot{{Start: slotStart, End: slotEnd}}}
+	w := f.do(http.MethodPut, "/api/v1/events/missing/availability/p1", "p1", body)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	requestID := w.Header().Get(handler.RequestIDHeader)
+	require.NotEmpty(t, requestID)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(f.logs.Bytes(), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, requestID, record["request_id"])
+	assert.Equal(t, "/api/v1/events/:id/availability/:user_id", record["route"])
+	assert.Equal(t, "p1", record["user_id"])
+	assert.Equal(t, "missing", record["event_id"])
+	assert.EqualValues(t, http.StatusNotFound, record["status"])
+	assert.Contains(t, record["error"], "missing")
+}
+
+func TestRequestLogger_ReusesClientRequestID(t *testing.T) {
+	f := newFixture(t)
+	f.logs.Reset()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events/e1", nil)
+	req.Header.Set("Authorization", "Bearer "+f.keys["p1"])
+	req.Header.Set(handler.RequestIDHeader, "trace-123")
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "trace-123", w.Header().Get(handler.RequestIDHeader))
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(f.logs.Bytes(), &record))
+	assert.Equal(t, "trace-123", record["request_id"])
+	assert.Equal(t, "e1", record["event_id"])
+	assert.Equal(t, "INFO", record["level"])
+}
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handler.CORS([]string{"https://app.example.com"}))
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/ping", nil)
+	preflight.Header.Set("Origin", "https://app.example.com")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, preflight)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+}
+
+func TestHealthCheck_Draining(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := handler.NewHandler(service.NewSchedulerService(
+		repository.NewInMemoryUserRepository(),
+		repository.NewInMemoryEventRepository(),
+		repository.NewInMemoryAvailabilityRepository(),
+	))
+	r := gin.New()
+	h.RegisterRoutes(r)
+
+	ping := func() int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, ping())
+	h.Drain()
+	assert.Equal(t, http.StatusServiceUnavailable, ping())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"draining"`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "a draining server is still alive")
+}
+
+// brokenEvents is an event repository whose store is unreachable.
+type brokenEvents struct{ repository.EventRepository }
+
+func (brokenEvents) Ping(context.Context) error { return errors.New("connection refused") }
+
+func (brokenEvents) List(context.Context) ([]*model.Event, error) {
+	return nil, errors.New("connection refused")
+}
+
+type brokenLinks struct{ repository.MagicLinkRepository }
+
+func (brokenLinks) Ping(context.Context) error { return errors.New("connection refused") }
+
+func TestReadiness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	readyz := func(events repository.EventRepository, links repository.MagicLinkRepository) (int, model.HealthReport) {
+		svc := service.NewSchedulerService(
+			repository.NewInMemoryUserRepository(), events, repository.NewInMemoryAvailabilityRepository(),
+			service.WithMagicLinks(links),
+		)
+		r := gin.New()
+		handler.NewHandler(svc).RegisterRoutes(r)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var report model.HealthReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return w.Code, report
+	}
+	components := func(report model.HealthReport) map[string]model.ComponentHealth {
+		byName := map[string]model.ComponentHealth{}
+		for _, c := range report.Components {
+			byName[c.Name] = c
+		}
+		return byName
+	}
+
+	code, report := readyz(repository.NewInMemoryEventRepository(), repository.NewInMemoryMagicLinkRepository())
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, model.HealthOK, report.Status)
+	assert.NotEmpty(t, report.Version)
+	assert.Len(t, report.Components, 7)
+
+	code, report = readyz(repository.NewInMemoryEventRepository(), brokenLinks{repository.NewInMemoryMagicLinkRepository()})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, model.HealthDegraded, report.Status)
+	assert.Equal(t, model.HealthDown, components(report)["magic_links"].Status)
+
+	code, report = readyz(brokenEvents{repository.NewInMemoryEventRepository()}, repository.NewInMemoryMagicLinkRepository())
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, model.HealthUnavailable, report.Status)
+	events := components(report)["events"]
+	assert.Equal(t, model.HealthDown, events.Status)
+	assert.True(t, events.Critical)
+	assert.Equal(t, "connection refused", events.Error)
+	assert.Equal(t, model.HealthUp, components(report)["users"].Status)
+}
+
+func TestListEvents_ReportsStoreErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := service.NewSchedulerService(
+		repository.NewInMemoryUserRepository(),
+		brokenEvents{repository.NewInMemoryEventRepository()},
+		repository.NewInMemoryAvailabilityRepository(),
+	)
+	registration, err := svc.RegisterUser(t.Context(), &model.User{ID: "u1", Name: "u1"})
+	require.NoError(t, err)
+	r := gin.New()
+	handler.NewHandler(svc).RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+	req.Header.Set("Authorization", "Bearer "+registration.Credential.Key)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), "connection refused")
+}
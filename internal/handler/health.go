@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/version"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ========== Health Probes ==========
+
+// @Summary Liveness probe
+// @Description Reports that the process is serving requests. Dependencies are not checked.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (h *Handler) liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": model.HealthOK, "version": version.String()})
+}
+
+// @Summary Readiness probe
+// @Description Checks every storage component and reports its status and latency. Answers 503 while shutting down or when a critical component is down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} model.HealthReport
+// @Failure 503 {object} model.HealthReport
+// @Router /readyz [get]
+func (h *Handler) readiness(c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, &model.HealthReport{
+			Status:     model.HealthDraining,
+			Version:    version.String(),
+			CheckedAt:  time.Now(),
+			Components: []model.ComponentHealth{},
+		})
+		return
+	}
+	report := h.svc.CheckHealth(c.Request.Context())
+	report.Version = version.String()
+	status := http.StatusOK
+	if report.Status == model.HealthUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
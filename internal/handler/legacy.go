@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ========== Legacy Routes ==========
+
+// The unversioned routes were deprecated when /api/v1 was added and will be
+// removed after legacySunset.
+var (
+	legacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	legacySunset       = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+)
+
+// registerLegacyRoutes keeps the routes that predate /api/v1 working. Each
+// is served by the same handler as its successor, behind deprecated.
+func (h *Handler) registerLegacyRoutes(r *gin.Engine) {
+	routes := []struct {
+		method, path string
+		// successor is the /api/v1 route that replaces this one. Parameters
+		// it shares with the legacy path are filled in for the Link header.
+		successor string
+		auth      gin.HandlerFunc
+		handle    gin.HandlerFunc
+	}{
+		{http.MethodPost, "/user", "/api/v1/users", nil, h.createUser},
+
+		{http.MethodGet, "/guest/event", "/api/v1/guest/event", h.authenticateGuest, h.getGuestEvent},
+		{http.MethodGet, "/guest/availability", "/api/v1/guest/availability", h.authenticateGuest, h.getGuestAvailability},
+		{http.MethodPut, "/guest/availability", "/api/v1/guest/availability", h.authenticateGuest, h.putGuestAvailability},
+
+		{http.MethodGet, "/me", "/api/v1/me", h.authenticate, h.getCurrentUser},
+		{http.MethodGet, "/me/apikeys", "/api/v1/me/api-keys", h.authenticate, h.listAPIKeys},
+		{http.MethodPost, "/me/apikeys", "/api/v1/me/api-keys", h.authenticate, h.issueAPIKey},
+		{http.MethodDelete, "/me/apikeys/:key_id", "/api/v1/me/api-keys/:key_id", h.authenticate, h.revokeAPIKey},
+
+		{http.MethodGet, "/user/:id", "/api/v1/users/:id", h.authenticate, h.getUser},
+		{http.MethodGet, "/users", "/api/v1/users", h.authenticate, h.getAllUsers},
+
+		{http.MethodGet, "/event/:id", "/api/v1/events/:id", h.authenticate, h.getEvent},
+		{http.MethodPost, "/event", "/api/v1/events", h.authenticate, h.createEvent},
+		{http.MethodPut, "/event", "/api/v1/events/:id", h.authenticate, h.updateEvent},
+		{http.MethodDelete, "/event/:id", "/api/v1/events/:id", h.authenticate, h.deleteEvent},
+		{http.MethodPost, "/event/:id/restore", "/api/v1/events/:id/restore", h.authenticate, h.undeleteEvent},
+		{http.MethodGet, "/events", "/api/v1/events", h.authenticate, h.listEvents},
+		{http.MethodGet, "/events/trash", "/api/v1/trash", h.authenticate, h.listTrash},
+		{http.MethodPost, "/event/:id/finalize", "/api/v1/events/:id/finalize", h.authenticate, h.finalizeEvent},
+		{http.MethodPost, "/events/schedule", "/api/v1/schedules", h.authenticate, h.scheduleBatch},
+
+		{http.MethodGet, "/event/:id/links", "/api/v1/events/:id/links", h.authenticate, h.listMagicLinks},
+		{http.MethodPost, "/event/:id/links", "/api/v1/events/:id/links", h.authenticate, h.createMagicLink},
+		{http.MethodDelete, "/event/:id/links/:link_id", "/api/v1/events/:id/links/:link_id", h.authenticate, h.revokeMagicLink},
+
+		{http.MethodGet, "/event/:id/availability/:user_id", "/api/v1/events/:id/availability/:user_id", h.authenticate, h.getAvailability},
+		{http.MethodPost, "/event/availability", "/api/v1/events/:id/availability/:user_id", h.authenticate, h.addAvailability},
+		{http.MethodPut, "/event/availability", "/api/v1/events/:id/availability/:user_id", h.authenticate, h.updateAvailability},
+		{http.MethodDelete, "/event/:id/availability/:user_id", "/api/v1/events/:id/availability/:user_id", h.authenticate, h.removeAvailability},
+		{http.MethodPost, "/event/:id/decline", "/api/v1/events/:id/decline", h.authenticate, h.declineEvent},
+		{http.MethodGet, "/event/:id/responses", "/api/v1/events/:id/responses", h.authenticate, h.getResponseSummary},
+
+		{http.MethodGet, "/event/:id/suggestions", "/api/v1/events/:id/suggestions", h.authenticate, h.suggestSlots},
+		{http.MethodGet, "/event/:id/suggestions/explain", "/api/v1/events/:id/suggestions/explain", h.authenticate, h.explainSuggestion},
+		{http.MethodGet, "/event/:id/suggestions/split", "/api/v1/events/:id/suggestions/split", h.authenticate, h.suggestSplitSessions},
+
+		{http.MethodGet, "/event/:id/history", "/api/v1/events/:id/history", h.authenticate, h.getEventHistory},
+		{http.MethodGet, "/admin/audit", "/api/v1/admin/audit", h.authenticate, h.queryAudit},
+
+		{http.MethodGet, "/event/:id/versions", "/api/v1/events/:id/versions", h.authenticate, h.listEventVersions},
+		{http.MethodGet, "/event/:id/versions/diff", "/api/v1/events/:id/versions/diff", h.authenticate, h.diffEventVersions},
+		{http.MethodGet, "/event/:id/versions/:version", "/api/v1/events/:id/versions/:version", h.authenticate, h.getEventVersion},
+		{http.MethodPost, "/event/:id/versions/:version/restore", "/api/v1/events/:id/versions/:version/restore", h.authenticate, h.restoreEventVersion},
+
+		{http.MethodGet, "/admin/export", "/api/v1/admin/export", h.authenticate, h.exportArchive},
+		{http.MethodPost, "/admin/import", "/api/v1/admin/import", h.authenticate, h.importArchive},
+	}
+	for _, route := range routes {
+		chain := []gin.HandlerFunc{deprecated(route.successor)}
+		if route.auth != nil {
+			chain = append(chain, route.auth)
+		}
+		r.Handle(route.method, route.path, append(chain, route.handle)...)
+	}
+}
+
+// deprecated marks responses as coming from a deprecated route, with the
+// date it goes away (RFC 9745 and RFC 8594). When the request names every
+// parameter of the successor route, a Link header points to it.
+func deprecated(successor string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(legacyDeprecatedAt.Unix(), 10)
+	sunset := legacySunset.Format(http.TimeFormat)
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunset)
+		if link, ok := fillParams(successor, c.Params); ok {
+			c.Header("Link", "<"+link+`>; rel="successor-version"`)
+		}
+		c.Next()
+	}
+}
+
+// fillParams replaces the :name segments of route with the values of params.
+func fillParams(route string, params gin.Params) (string, bool) {
+	segments := strings.Split(route, "/")
+	for i, s := range segments {
+		if !strings.HasPrefix(s, ":") {
+			continue
+		}
+		v, ok := params.Get(s[1:])
+		if !ok {
+			return "", false
+		}
+		segments[i] = url.PathEscape(v)
+	}
+	return strings.Join(segments, "/"), true
+}
@@ -0,0 +1,102 @@
+package handler_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"meeting-scheduler/internal/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyRoutes(t *testing.T) {
+	event := model.Event{
+		ID: "e1", Title: "Renamed", DurationMin: 60,
+		Participants: []string{"org", "p1"},
+		Slots:        []model.Slot{{Start: slotStart, End: slotEnd}},
+	}
+	ownAvailability := model.Availability{EventID: "e1", Slots: []model.Slot{{Start: slotStart, End: slotEnd}}}
+	finalize := model.FinalizeRequest{Sessions: []model.Slot{{Start: slotStart, End: slotStart.Add(time.Hour)}}}
+	archive := model.Archive{Kind: model.ArchiveKind, Version: model.ArchiveVersion}
+
+	tests := []struct {
+		method string
+		path   string
+		actor  string
+		body   any
+		want   int
+		// successor is the expected Link target, if the request names
+		// every parameter of the successor route.
+		successor string
+	}{
+		{http.MethodPost, "/user", "", model.User{ID: "new", Name: "New"}, http.StatusCreated, "/api/v1/users"},
+
+		{http.MethodGet, "/guest/event", "", nil, http.StatusUnauthorized, "/api/v1/guest/event"},
+		{http.MethodGet, "/guest/availability", "", nil, http.StatusUnauthorized, "/api/v1/guest/availability"},
+		{http.MethodPut, "/guest/availability", "", ownAvailability, http.StatusUnauthorized, "/api/v1/guest/availability"},
+
+		{http.MethodGet, "/me", "p1", nil, http.StatusOK, "/api/v1/me"},
+		{http.MethodGet, "/me/apikeys", "p1", nil, http.StatusOK, "/api/v1/me/api-keys"},
+		{http.MethodPost, "/me/apikeys", "p1", nil, http.StatusCreated, "/api/v1/me/api-keys"},
+		{http.MethodDelete, "/me/apikeys/unknown", "p1", nil, http.StatusNotFound, "/api/v1/me/api-keys/unknown"},
+
+		{http.MethodGet, "/user/p1", "out", nil, http.StatusOK, "/api/v1/users/p1"},
+		{http.MethodGet, "/users", "out", nil, http.StatusOK, "/api/v1/users"},
+
+		{http.MethodGet, "/event/e1", "out", nil, http.StatusOK, "/api/v1/events/e1"},
+		{http.MethodPost, "/event", "p1", model.Event{ID: "e2", DurationMin: 30, Participants: []string{"p1"}}, http.StatusCreated, "/api/v1/events"},
+		{http.MethodPut, "/event", "org", event, http.StatusOK, ""},
+		{http.MethodDelete, "/event/e1", "org", nil, http.StatusOK, "/api/v1/events/e1"},
+		{http.MethodPost, "/event/e1/restore", "org", nil, http.StatusNotFound, "/api/v1/events/e1/restore"},
+		{http.MethodGet, "/events", "out", nil, http.StatusOK, "/api/v1/events"},
+		{http.MethodGet, "/events/trash", "org", nil, http.StatusOK, "/api/v1/trash"},
+		{http.MethodPost, "/event/e1/finalize", "org", finalize, http.StatusOK, "/api/v1/events/e1/finalize"},
+		{http.MethodPost, "/events/schedule", "p1", model.BatchScheduleRequest{EventIDs: []string{"e1"}}, http.StatusOK, "/api/v1/schedules"},
+
+		{http.MethodGet, "/event/e1/links", "org", nil, http.StatusOK, "/api/v1/events/e1/links"},
+		{http.MethodPost, "/event/e1/links", "org", model.MagicLinkRequest{Name: "Guest"}, http.StatusCreated, "/api/v1/events/e1/links"},
+		{http.MethodDelete, "/event/e1/links/unknown", "org", nil, http.StatusNotFound, "/api/v1/events/e1/links/unknown"},
+
+		{http.MethodGet, "/event/e1/availability/p1", "org", nil, http.StatusOK, "/api/v1/events/e1/availability/p1"},
+		{http.MethodPost, "/event/availability", "org", ownAvailability, http.StatusCreated, ""},
+		{http.MethodPut, "/event/availability", "p1", ownAvailability, http.StatusOK, ""},
+		{http.MethodDelete, "/event/e1/availability/p1", "p1", nil, http.StatusOK, "/api/v1/events/e1/availability/p1"},
+		{http.MethodPost, "/event/e1/decline", "p1", nil, http.StatusOK, "/api/v1/events/e1/decline"},
+		{http.MethodGet, "/event/e1/responses", "p1", nil, http.StatusOK, "/api/v1/events/e1/responses"},
+
+		{http.MethodGet, "/event/e1/suggestions", "p1", nil, http.StatusOK, "/api/v1/events/e1/suggestions"},
+		{http.MethodGet, "/event/e1/suggestions/explain?start=" + slotStart.Format(time.RFC3339), "p1", nil, http.StatusOK, "/api/v1/events/e1/suggestions/explain"},
+		{http.MethodGet, "/event/e1/suggestions/split", "p1", nil, http.StatusBadRequest, "/api/v1/events/e1/suggestions/split"},
+
+		{http.MethodGet, "/event/e1/history", "p1", nil, http.StatusOK, "/api/v1/events/e1/history"},
+		{http.MethodGet, "/admin/audit", "admin", nil, http.StatusOK, "/api/v1/admin/audit"},
+
+		{http.MethodGet, "/event/e1/versions", "p1", nil, http.StatusOK, "/api/v1/events/e1/versions"},
+		{http.MethodGet, "/event/e1/versions/diff?from=1&to=2", "p1", nil, http.StatusOK, "/api/v1/events/e1/versions/diff"},
+		{http.MethodGet, "/event/e1/versions/1", "p1", nil, http.StatusOK, "/api/v1/events/e1/versions/1"},
+		{http.MethodPost, "/event/e1/versions/1/restore", "org", nil, http.StatusOK, "/api/v1/events/e1/versions/1/restore"},
+
+		{http.MethodGet, "/admin/export", "admin", nil, http.StatusOK, "/api/v1/admin/export"},
+		{http.MethodPost, "/admin/import?dry_run=true", "admin", archive, http.StatusOK, "/api/v1/admin/import"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			f := newFixture(t)
+			w := f.do(tt.method, tt.path, tt.actor, tt.body)
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+
+			assert.Regexp(t, `^@\d+$`, w.Header().Get("Deprecation"))
+			sunset, err := http.ParseTime(w.Header().Get("Sunset"))
+			require.NoError(t, err)
+			assert.True(t, sunset.After(slotStart))
+			if tt.successor == "" {
+				assert.Empty(t, w.Header().Get("Link"))
+			} else {
+				assert.Equal(t, "<"+tt.successor+`>; rel="successor-version"`, w.Header().Get("Link"))
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"meeting-scheduler/internal/logging"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID. A well-formed ID sent by the
+// client is reused so calls can be traced across services; otherwise one is
+// generated. Either way it is echoed in the response.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestLogger gives each request an ID, opens a logging scope for it in the
+// request context and writes one access log record when the request is done.
+// Errors attached with respondError are included in that record.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+
+		attrs := []slog.Attr{
+			slog.String(logging.KeyRequestID, id),
+			slog.String(logging.KeyRoute, c.FullPath()),
+		}
+		if eventID := c.Param("id"); eventID != "" && logging.IsEventRoute(c.FullPath()) {
+			attrs = append(attrs, slog.String(logging.KeyEventID, eventID))
+		}
+		ctx := logging.NewContext(c.Request.Context(), attrs...)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		fields := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if err := c.Errors.Last(); err != nil {
+			fields = append(fields, slog.String("error", err.Error()))
+		}
+		logger.LogAttrs(ctx, level, "request", fields...)
+	}
+}
+
+// Recoverer turns panics into 500 responses and logs them with the request's
+// scope. It must run after RequestLogger.
+func Recoverer(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		err := fmt.Errorf("panic: %v", recovered)
+		logger.ErrorContext(c.Request.Context(), "recovered from panic", "error", err)
+		_ = c.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+	})
+}
+
+// respondError writes err as the response body and attaches it to the
+// request so the access log records the cause.
+func respondError(c *gin.Context, status int, err error) {
+	_ = c.Error(err)
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
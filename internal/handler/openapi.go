@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"meeting-scheduler/docs"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// ========== API Documentation ==========
+
+// openAPIDocument is the OpenAPI document generated from the annotations on
+// the handlers. Run `go generate ./cmd/server` after changing them.
+var openAPIDocument = sync.OnceValue(func() []byte {
+	return []byte(docs.SwaggerInfo.ReadDoc())
+})
+
+func (h *Handler) openAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openAPIDocument())
+}
+
+var swaggerFilesHandler = ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL("/openapi.json"))
+
+// swaggerUI serves the Swagger UI pages, pointed at /openapi.json.
+func (h *Handler) swaggerUI(c *gin.Context) {
+	if c.Param("any") == "/" {
+		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
+		return
+	}
+	swaggerFilesHandler(c)
+}
@@ -0,0 +1,274 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPISpec is the part of the OpenAPI document the tests check.
+type openAPISpec struct {
+	Paths       map[string]map[string]*operation `json:"paths"`
+	Definitions map[string]*schema               `json:"definitions"`
+}
+
+type operation struct {
+	Parameters []parameter `json:"parameters"`
+	Responses  map[string]struct {
+		Schema *schema `json:"schema"`
+	} `json:"responses"`
+	Security []map[string][]string `json:"security"`
+}
+
+// authenticated reports whether op says how to pass credentials: as an API
+// key, or as the magic link token of guest routes.
+func (op *operation) authenticated() bool {
+	return len(op.Security) > 0 || slices.ContainsFunc(op.Parameters, func(p parameter) bool {
+		return p.Name == "token" && p.In == "query"
+	})
+}
+
+type parameter struct {
+	Name string `json:"name"`
+	In   string `json:"in"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Items                *schema            `json:"items"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+	AllOf                []*schema          `json:"allOf"`
+}
+
+// additional returns the schema of map values, if s has a typed one.
+func (s *schema) additional() *schema {
+	var values schema
+	if json.Unmarshal(s.AdditionalProperties, &values) != nil {
+		return nil
+	}
+	return &values
+}
+
+// freeForm reports whether s says nothing about the value it describes, as
+// swag renders fields it has no type for.
+func (s *schema) freeForm() bool {
+	return s.Ref == "" && len(s.AllOf) == 0 && s.Type == "object" && s.Properties == nil && s.additional() == nil
+}
+
+// routes that are not part of the API the document describes.
+var undocumentedRoutes = []string{"/openapi.json", "/swagger/*any"}
+
+func loadSpec(t *testing.T, f *fixture) *openAPISpec {
+	t.Helper()
+	w := f.do(http.MethodGet, "/openapi.json", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var spec openAPISpec
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+	return &spec
+}
+
+// openAPIPath turns a gin route such as /event/:id into /event/{id}.
+func openAPIPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	f := newFixture(t)
+	spec := loadSpec(t, f)
+
+	registered := map[string]bool{}
+	for _, route := range f.router.Routes() {
+		if slices.Contains(undocumentedRoutes, route.Path) {
+			continue
+		}
+		// Without credentials a route either answers or asks for them.
+		w := f.do(route.Method, strings.ReplaceAll(route.Path, ":", "x"), "", nil)
+		if w.Header().Get("Deprecation") != "" {
+			// Legacy routes are left out of the document; their successors
+			// are in it.
+			assert.NotEmpty(t, w.Header().Get("Sunset"), "%s %s has no sunset date", route.Method, route.Path)
+			if link := w.Header().Get("Link"); link != "" {
+				successor := strings.TrimPrefix(strings.TrimSuffix(link, `>; rel="successor-version"`), "<")
+				op, _ := spec.operation(route.Method, successor)
+				assert.NotNil(t, op, "successor %s of %s %s is not documented", successor, route.Method, route.Path)
+			}
+			continue
+		}
+		path, method := openAPIPath(route.Path), strings.ToLower(route.Method)
+		registered[method+" "+path] = true
+		op, ok := spec.Paths[path][method]
+		if !assert.True(t, ok, "%s %s is not documented", route.Method, route.Path) {
+			continue
+		}
+
+		var want, got []string
+		for _, s := range strings.Split(route.Path, "/") {
+			if strings.HasPrefix(s, ":") {
+				want = append(want, s[1:])
+			}
+		}
+		for _, p := range op.Parameters {
+			if p.In == "path" {
+				got = append(got, p.Name)
+			}
+		}
+		sort.Strings(want)
+		sort.Strings(got)
+		assert.Equal(t, want, got, "path parameters of %s %s", route.Method, route.Path)
+
+		if w.Code == http.StatusUnauthorized {
+			_, ok := op.Responses["401"]
+			assert.True(t, ok, "%s %s requires credentials but does not document status 401", route.Method, route.Path)
+			assert.True(t, op.authenticated(), "%s %s requires credentials but does not document them", route.Method, route.Path)
+		}
+		for status, resp := range op.Responses {
+			if strings.HasPrefix(status, "2") && resp.Schema != nil {
+				assert.False(t, resp.Schema.freeForm(), "%s %s documents its %s response as an untyped object", route.Method, route.Path, status)
+			}
+		}
+	}
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			assert.True(t, registered[method+" "+path], "%s %s is documented but not registered", strings.ToUpper(method), path)
+		}
+	}
+}
+
+// assertDocumented checks that the spec documents the status of a response
+// to method and path, and that its body has the documented shape.
+func assertDocumented(t *testing.T, spec *openAPISpec, method, path string, w *httptest.ResponseRecorder) {
+	t.Helper()
+	op, template := spec.operation(method, path)
+	if op == nil {
+		return
+	}
+	resp, ok := op.Responses[fmt.Sprint(w.Code)]
+	if !assert.True(t, ok, "%s %s does not document status %d", method, template, w.Code) || resp.Schema == nil {
+		return
+	}
+	var body any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	for _, err := range spec.validate(body, resp.Schema, "$") {
+		assert.Fail(t, "response does not match the spec", "%s %s %d: %v", method, template, w.Code, err)
+	}
+}
+
+// operation returns the operation whose path template matches path,
+// preferring literal segments over parameters.
+func (s *openAPISpec) operation(method, path string) (*operation, string) {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(path, "/")
+	var best *operation
+	var bestTemplate string
+	bestLiterals := -1
+	for template, ops := range s.Paths {
+		op, ok := ops[strings.ToLower(method)]
+		parts := strings.Split(template, "/")
+		if !ok || len(parts) != len(segments) {
+			continue
+		}
+		literals := 0
+		for i, part := range parts {
+			switch {
+			case strings.HasPrefix(part, "{"):
+			case part == segments[i]:
+				literals++
+			default:
+				literals = -1
+			}
+			if literals < 0 {
+				break
+			}
+		}
+		if literals > bestLiterals {
+			best, bestTemplate, bestLiterals = op, template, literals
+		}
+	}
+	return best, bestTemplate
+}
+
+// validate returns how v departs from sch. Fields are checked against the
+// documented properties and types; null is accepted anywhere, as Go encodes
+// nil slices and pointers as null.
+func (s *openAPISpec) validate(v any, sch *schema, at string) []error {
+	if v == nil {
+		return nil
+	}
+	if sch.Ref != "" {
+		def, ok := s.Definitions[strings.TrimPrefix(sch.Ref, "#/definitions/")]
+		if !ok {
+			return []error{fmt.Errorf("%s: unknown definition %s", at, sch.Ref)}
+		}
+		return s.validate(v, def, at)
+	}
+	var errs []error
+	for _, part := range sch.AllOf {
+		errs = append(errs, s.validate(v, part, at)...)
+	}
+	mismatch := func() []error { return append(errs, fmt.Errorf("%s: got %T, documented as %s", at, v, sch.Type)) }
+	switch sch.Type {
+	case "object":
+		if sch.freeForm() {
+			break
+		}
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return mismatch()
+		}
+		for k, fv := range obj {
+			switch {
+			case sch.Properties != nil:
+				prop, ok := sch.Properties[k]
+				if !ok {
+					errs = append(errs, fmt.Errorf("%s.%s is not documented", at, k))
+					continue
+				}
+				errs = append(errs, s.validate(fv, prop, at+"."+k)...)
+			case sch.additional() != nil:
+				errs = append(errs, s.validate(fv, sch.additional(), at+"."+k)...)
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return mismatch()
+		}
+		for i, item := range arr {
+			errs = append(errs, s.validate(item, sch.Items, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return mismatch()
+		}
+	case "integer":
+		if n, ok := v.(float64); !ok || n != math.Trunc(n) {
+			return mismatch()
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return mismatch()
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return mismatch()
+		}
+	}
+	return errs
+}
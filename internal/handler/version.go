@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ========== Version Handlers ==========
+
+// @Summary List event versions
+// @Description List every recorded version of the event and its availability set, oldest first
+// @Tags version
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {array} model.EventVersion
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/versions [get]
+func (h *Handler) listEventVersions(c *gin.Context) {
+	versions, err := h.svc.ListEventVersions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// @Summary Get an event version
+// @Description Get the event and its availability set as recorded in one version
+// @Tags version
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param version path int true "Version number"
+// @Success 200 {object} model.EventVersion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/versions/{version} [get]
+func (h *Handler) getEventVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be an integer"})
+		return
+	}
+	v, err := h.svc.GetEventVersion(c.Request.Context(), c.Param("id"), version)
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
+
+// @Summary Diff two event versions
+// @Description Show the event fields and availability entries that changed between two versions
+// @Tags version
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param from query int true "Earlier version"
+// @Param to query int true "Later version"
+// @Success 200 {object} model.VersionDiff
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/versions/diff [get]
+func (h *Handler) diffEventVersions(c *gin.Context) {
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be version numbers"})
+		return
+	}
+	diff, err := h.svc.DiffEventVersions(c.Request.Context(), c.Param("id"), from, to)
+	if err != nil {
+		respondError(c, http.StatusNotFound, err)
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
+// @Summary Restore an event version
+// @Description Bring the event and its availability set back to an earlier version. The restore is recorded as a new version. Only organizers may restore.
+// @Tags version
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Event ID"
+// @Param version path int true "Version to restore"
+// @Success 200 {object} model.EventVersion
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/events/{id}/versions/{version}/restore [post]
+func (h *Handler) restoreEventVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version must be an integer"})
+		return
+	}
+	v, err := h.svc.RestoreEventVersion(c.Request.Context(), currentUser(c).ID, c.Param("id"), version)
+	if err != nil {
+		respondError(c, statusFor(err, http.StatusBadRequest), err)
+		return
+	}
+	c.JSON(http.StatusOK, v)
+}
@@ -0,0 +1,115 @@
+// Package logging sets up structured JSON logging and carries request-scoped
+// attributes, such as the request ID, route and acting user, through
+// context.Context so every record logged for a request can be correlated.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// New returns a JSON logger that adds the request-scoped attributes found in
+// the context of each record.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	return slog.New(&contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+// ParseLevel accepts debug, info, warn or error, case-insensitively.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.TrimSpace(s)))
+	return level, err
+}
+
+type scopeKey struct{}
+
+// scope holds the attributes of one request. Middleware and services add to
+// it as they learn more about the request.
+type scope struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// NewContext returns a copy of ctx carrying a fresh request scope that starts
+// with attrs.
+func NewContext(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{attrs: attrs})
+}
+
+// AddAttrs records attrs in the request scope of ctx, replacing attributes
+// with the same key. It does nothing when ctx carries no scope.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	sc, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+next:
+	for _, a := range attrs {
+		for i := range sc.attrs {
+			if sc.attrs[i].Key == a.Key {
+				sc.attrs[i] = a
+				continue next
+			}
+		}
+		sc.attrs = append(sc.attrs, a)
+	}
+}
+
+// Attrs returns the attributes recorded in the request scope of ctx.
+func Attrs(ctx context.Context) []slog.Attr {
+	sc, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return nil
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return append([]slog.Attr(nil), sc.attrs...)
+}
+
+// RequestID returns the ID of the request ctx belongs to, or "".
+func RequestID(ctx context.Context) string {
+	for _, a := range Attrs(ctx) {
+		if a.Key == KeyRequestID {
+			return a.Value.String()
+		}
+	}
+	return ""
+}
+
+// Attribute keys shared by the handler and service layers.
+const (
+	KeyRequestID = "request_id"
+	KeyRoute     = "route"
+	KeyUserID    = "user_id"
+	KeyGuestID   = "guest_id"
+	KeyEventID   = "event_id"
+	KeyTraceID   = "trace_id"
+)
+
+// IsEventRoute reports whether the :id parameter of route is an event ID, so
+// request middleware knows when to record it under KeyEventID.
+func IsEventRoute(route string) bool {
+	return strings.HasPrefix(route, "/api/v1/events/") || strings.HasPrefix(route, "/event/")
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(Attrs(ctx)...)
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,55 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"meeting-scheduler/internal/logging"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_AddsRequestScope(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	ctx := logging.NewContext(context.Background(), slog.String(logging.KeyRequestID, "req-1"))
+	logging.AddAttrs(ctx, slog.String(logging.KeyEventID, "e1"))
+	logging.AddAttrs(ctx, slog.String(logging.KeyEventID, "e2"))
+	logger.ErrorContext(ctx, "failed", "error", "boom")
+	logger.DebugContext(ctx, "hidden")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "failed", record["msg"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, "e2", record["event_id"])
+	assert.Equal(t, "boom", record["error"])
+	assert.Equal(t, "req-1", logging.RequestID(ctx))
+}
+
+func TestAddAttrs_WithoutScope(t *testing.T) {
+	ctx := context.Background()
+	logging.AddAttrs(ctx, slog.String(logging.KeyUserID, "u1"))
+	assert.Empty(t, logging.Attrs(ctx))
+	assert.Empty(t, logging.RequestID(ctx))
+}
+
+func TestIsEventRoute(t *testing.T) {
+	assert.True(t, logging.IsEventRoute("/api/v1/events/:id/availability/:user_id"))
+	assert.True(t, logging.IsEventRoute("/event/:id/suggestions"))
+	assert.False(t, logging.IsEventRoute("/api/v1/users/:id"))
+	assert.False(t, logging.IsEventRoute("/api/v1/events"))
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := logging.ParseLevel("WARN")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelWarn, level)
+
+	_, err = logging.ParseLevel("loud")
+	assert.Error(t, err)
+}
@@ -0,0 +1,162 @@
+// Package metrics exposes Prometheus metrics for HTTP traffic, repository
+// operations, stored record counts and suggestion computations.
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"meeting-scheduler/internal/model"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "scheduler"
+
+// Metrics owns a registry and the collectors registered on it.
+type Metrics struct {
+	registry           *prometheus.Registry
+	httpRequests       *prometheus.CounterVec
+	httpDuration       *prometheus.HistogramVec
+	repoDuration       *prometheus.HistogramVec
+	suggestionDuration *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		repoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Repository call latency, by repository and operation.",
+			Buckets:   []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+		}, []string{"repository", "operation"}),
+		suggestionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "suggestion_duration_seconds",
+			Help:      "Time spent computing suggestions, by kind and event size (participants × candidate windows).",
+			Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5},
+		}, []string{"kind", "size"}),
+	}
+	m.registry.MustRegister(
+		m.httpRequests, m.httpDuration, m.repoDuration, m.suggestionDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware counts and times requests by their route template rather than
+// the raw path, so /api/v1/events/e1 and /api/v1/events/e2 share a series.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		m.httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveSuggestion implements service.Observer.
+func (m *Metrics) ObserveSuggestion(kind string, participants, windows int, d time.Duration) {
+	m.suggestionDuration.WithLabelValues(kind, sizeBucket(participants*windows)).Observe(d.Seconds())
+}
+
+func (m *Metrics) observeRepo(repository, operation string, start time.Time) {
+	m.repoDuration.WithLabelValues(repository, operation).Observe(time.Since(start).Seconds())
+}
+
+// sizeBucket keeps the size label's cardinality fixed.
+func sizeBucket(size int) string {
+	switch {
+	case size <= 100:
+		return "<=100"
+	case size <= 1000:
+		return "<=1000"
+	case size <= 10000:
+		return "<=10000"
+	default:
+		return ">10000"
+	}
+}
+
+// CountRecords exports the number of stored records of each kind, as
+// reported by count. Counting scans the store, so a result is reused by
+// every scrape within maxAge of it.
+func (m *Metrics) CountRecords(count func(context.Context) (*model.RecordCounts, error), maxAge time.Duration) {
+	m.registry.MustRegister(&recordCollector{
+		count:  count,
+		maxAge: maxAge,
+		desc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "records"),
+			"Stored records, by entity.", []string{"entity"}, nil),
+	})
+}
+
+type recordCollector struct {
+	count  func(context.Context) (*model.RecordCounts, error)
+	maxAge time.Duration
+	desc   *prometheus.Desc
+
+	mu        sync.Mutex
+	cached    *model.RecordCounts
+	countedAt time.Time
+}
+
+func (c *recordCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *recordCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.counts()
+	if err != nil {
+		slog.Error("counting records for metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts.Users), model.EntityUser)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts.Events), model.EntityEvent)
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts.Availability), model.EntityAvailability)
+}
+
+// counts returns the cached counts while they are fresh. Failures are not
+// cached, so the next scrape counts again.
+func (c *recordCollector) counts() (*model.RecordCounts, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cached != nil && time.Since(c.countedAt) < c.maxAge {
+		return c.cached, nil
+	}
+	counts, err := c.count(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	c.cached, c.countedAt = counts, time.Now()
+	return counts, nil
+}
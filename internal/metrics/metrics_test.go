@@ -0,0 +1,108 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"meeting-scheduler/internal/handler"
+	"meeting-scheduler/internal/metrics"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := metrics.New()
+	svc := service.NewSchedulerService(
+		m.InstrumentUserRepository(repository.NewInMemoryUserRepository()),
+		m.InstrumentEventRepository(repository.NewInMemoryEventRepository()),
+		m.InstrumentAvailabilityRepository(repository.NewInMemoryAvailabilityRepository()),
+		service.WithObserver(m),
+	)
+	m.CountRecords(svc.CountRecords, 0)
+
+	r := gin.New()
+	r.Use(m.Middleware())
+	handler.NewHandler(svc).RegisterRoutes(r)
+	r.GET("/metrics", gin.WrapH(m.Handler()))
+
+	do := func(method, path, key string, body any) *httptest.ResponseRecorder {
+		var payload io.Reader
+		if body != nil {
+			b, _ := json.Marshal(body)
+			payload = bytes.NewReader(b)
+		}
+		req := httptest.NewRequest(method, path, payload)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/user", "", model.User{ID: "u1", Name: "One"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var registration model.Registration
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &registration))
+	key := registration.Credential.Key
+
+	start := time.Date(2025, time.May, 20, 9, 0, 0, 0, time.UTC)
+	slots := []model.Slot{{Start: start, End: start.Add(2 * time.Hour)}}
+	w = do(http.MethodPost, "/event", key, model.Event{ID: "e1", DurationMin: 60, Participants: []string{"u1"}, Slots: slots})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = do(http.MethodPost, "/event/availability", key, model.Availability{EventID: "e1", Slots: slots})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = do(http.MethodGet, "/event/e1/suggestions", key, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = do(http.MethodGet, "/metrics", "", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	body := w.Body.String()
+
+	for _, want := range []string{
+		`scheduler_http_requests_total{method="POST",route="/user",status="201"} 1`,
+		`scheduler_http_request_duration_seconds_count{method="GET",route="/event/:id/suggestions",status="200"} 1`,
+		`scheduler_repository_operation_duration_seconds_count{operation="create",repository="event"} 1`,
+		`scheduler_repository_operation_duration_seconds_count{operation="get_by_event",repository="availability"}`,
+		`scheduler_records{entity="user"} 1`,
+		`scheduler_records{entity="event"} 1`,
+		`scheduler_records{entity="availability"} 1`,
+		`scheduler_suggestion_duration_seconds_count{kind="slots",size="<=100"} 1`,
+	} {
+		assert.Contains(t, body, want)
+	}
+}
+
+func TestCountRecords_ReusesFreshCounts(t *testing.T) {
+	m := metrics.New()
+	calls := 0
+	m.CountRecords(func(context.Context) (*model.RecordCounts, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("store unavailable")
+		}
+		return &model.RecordCounts{Users: calls}, nil
+	}, time.Hour)
+
+	scrape := func() string {
+		w := httptest.NewRecorder()
+		m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return w.Body.String()
+	}
+	assert.NotContains(t, scrape(), "scheduler_records{")
+	assert.Contains(t, scrape(), `scheduler_records{entity="user"} 2`, "a failed count is not cached")
+	assert.Contains(t, scrape(), `scheduler_records{entity="user"} 2`)
+	assert.Equal(t, 2, calls, "fresh counts are reused")
+}
@@ -0,0 +1,293 @@
+package metrics
+
+import (
+	"context"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"time"
+)
+
+// The types below decorate each repository interface, timing every call
+// under the repository and operation labels.
+
+type userRepository struct {
+	next repository.UserRepository
+	m    *Metrics
+}
+
+// InstrumentUserRepository times every call to r.
+func (m *Metrics) InstrumentUserRepository(r repository.UserRepository) repository.UserRepository {
+	return &userRepository{next: r, m: m}
+}
+
+func (r *userRepository) Get(ctx context.Context, id string) (*model.User, error) {
+	defer r.m.observeRepo("user", "get", time.Now())
+	return r.next.Get(ctx, id)
+}
+
+func (r *userRepository) GetAll(ctx context.Context) (map[string]*model.User, error) {
+	defer r.m.observeRepo("user", "get_all", time.Now())
+	return r.next.GetAll(ctx)
+}
+
+func (r *userRepository) Create(ctx context.Context, user *model.User) error {
+	defer r.m.observeRepo("user", "create", time.Now())
+	return r.next.Create(ctx, user)
+}
+
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	defer r.m.observeRepo("user", "delete", time.Now())
+	return r.next.Delete(ctx, id)
+}
+
+type eventRepository struct {
+	next repository.EventRepository
+	m    *Metrics
+}
+
+// InstrumentEventRepository times every call to r.
+func (m *Metrics) InstrumentEventRepository(r repository.EventRepository) repository.EventRepository {
+	return &eventRepository{next: r, m: m}
+}
+
+func (r *eventRepository) Create(ctx context.Context, event *model.Event) error {
+	defer r.m.observeRepo("event", "create", time.Now())
+	return r.next.Create(ctx, event)
+}
+
+func (r *eventRepository) Get(ctx context.Context, id string) (*model.Event, error) {
+	defer r.m.observeRepo("event", "get", time.Now())
+	return r.next.Get(ctx, id)
+}
+
+func (r *eventRepository) Update(ctx context.Context, event *model.Event) error {
+	defer r.m.observeRepo("event", "update", time.Now())
+	return r.next.Update(ctx, event)
+}
+
+func (r *eventRepository) Delete(ctx context.Context, id string) error {
+	defer r.m.observeRepo("event", "delete", time.Now())
+	return r.next.Delete(ctx, id)
+}
+
+func (r *eventRepository) List(ctx context.Context) ([]*model.Event, error) {
+	defer r.m.observeRepo("event", "list", time.Now())
+	return r.next.List(ctx)
+}
+
+func (r *eventRepository) AllEventIds(ctx context.Context) (map[string]struct{}, error) {
+	defer r.m.observeRepo("event", "all_event_ids", time.Now())
+	return r.next.AllEventIds(ctx)
+}
+
+func (r *eventRepository) Trash(ctx context.Context, id string, at time.Time) error {
+	defer r.m.observeRepo("event", "trash", time.Now())
+	return r.next.Trash(ctx, id, at)
+}
+
+func (r *eventRepository) Restore(ctx context.Context, id string) error {
+	defer r.m.observeRepo("event", "restore", time.Now())
+	return r.next.Restore(ctx, id)
+}
+
+func (r *eventRepository) GetTrashed(ctx context.Context, id string) (*model.Event, error) {
+	defer r.m.observeRepo("event", "get_trashed", time.Now())
+	return r.next.GetTrashed(ctx, id)
+}
+
+func (r *eventRepository) ListTrashed(ctx context.Context) ([]*model.Event, error) {
+	defer r.m.observeRepo("event", "list_trashed", time.Now())
+	return r.next.ListTrashed(ctx)
+}
+
+type availabilityRepository struct {
+	next repository.AvailabilityRepository
+	m    *Metrics
+}
+
+// InstrumentAvailabilityRepository times every call to r.
+func (m *Metrics) InstrumentAvailabilityRepository(r repository.AvailabilityRepository) repository.AvailabilityRepository {
+	return &availabilityRepository{next: r, m: m}
+}
+
+func (r *availabilityRepository) Get(ctx context.Context, eventID, userID string) (model.Availability, error) {
+	defer r.m.observeRepo("availability", "get", time.Now())
+	return r.next.Get(ctx, eventID, userID)
+}
+
+func (r *availabilityRepository) Create(ctx context.Context, av model.Availability) error {
+	defer r.m.observeRepo("availability", "create", time.Now())
+	return r.next.Create(ctx, av)
+}
+
+func (r *availabilityRepository) Update(ctx context.Context, av model.Availability) error {
+	defer r.m.observeRepo("availability", "update", time.Now())
+	return r.next.Update(ctx, av)
+}
+
+func (r *availabilityRepository) GetByEvent(ctx context.Context, eventID string) (map[string]model.Availability, error) {
+	defer r.m.observeRepo("availability", "get_by_event", time.Now())
+	return r.next.GetByEvent(ctx, eventID)
+}
+
+func (r *availabilityRepository) Delete(ctx context.Context, eventID, userID string) error {
+	defer r.m.observeRepo("availability", "delete", time.Now())
+	return r.next.Delete(ctx, eventID, userID)
+}
+
+func (r *availabilityRepository) DeleteByEvent(ctx context.Context, eventID string) error {
+	defer r.m.observeRepo("availability", "delete_by_event", time.Now())
+	return r.next.DeleteByEvent(ctx, eventID)
+}
+
+type credentialRepository struct {
+	next repository.CredentialRepository
+	m    *Metrics
+}
+
+// InstrumentCredentialRepository times every call to r.
+func (m *Metrics) InstrumentCredentialRepository(r repository.CredentialRepository) repository.CredentialRepository {
+	return &credentialRepository{next: r, m: m}
+}
+
+func (r *credentialRepository) Create(ctx context.Context, cred *model.Credential) error {
+	defer r.m.observeRepo("credential", "create", time.Now())
+	return r.next.Create(ctx, cred)
+}
+
+func (r *credentialRepository) GetByKeyHash(ctx context.Context, hash string) (*model.Credential, error) {
+	defer r.m.observeRepo("credential", "get_by_key_hash", time.Now())
+	return r.next.GetByKeyHash(ctx, hash)
+}
+
+func (r *credentialRepository) ListByUser(ctx context.Context, userID string) ([]*model.Credential, error) {
+	defer r.m.observeRepo("credential", "list_by_user", time.Now())
+	return r.next.ListByUser(ctx, userID)
+}
+
+func (r *credentialRepository) Delete(ctx context.Context, id string) error {
+	defer r.m.observeRepo("credential", "delete", time.Now())
+	return r.next.Delete(ctx, id)
+}
+
+type magicLinkRepository struct {
+	next repository.MagicLinkRepository
+	m    *Metrics
+}
+
+// InstrumentMagicLinkRepository times every call to r.
+func (m *Metrics) InstrumentMagicLinkRepository(r repository.MagicLinkRepository) repository.MagicLinkRepository {
+	return &magicLinkRepository{next: r, m: m}
+}
+
+func (r *magicLinkRepository) Create(ctx context.Context, link *model.MagicLink) error {
+	defer r.m.observeRepo("magic_link", "create", time.Now())
+	return r.next.Create(ctx, link)
+}
+
+func (r *magicLinkRepository) Get(ctx context.Context, id string) (*model.MagicLink, error) {
+	defer r.m.observeRepo("magic_link", "get", time.Now())
+	return r.next.Get(ctx, id)
+}
+
+func (r *magicLinkRepository) Update(ctx context.Context, link *model.MagicLink) error {
+	defer r.m.observeRepo("magic_link", "update", time.Now())
+	return r.next.Update(ctx, link)
+}
+
+func (r *magicLinkRepository) ListByEvent(ctx context.Context, eventID string) ([]*model.MagicLink, error) {
+	defer r.m.observeRepo("magic_link", "list_by_event", time.Now())
+	return r.next.ListByEvent(ctx, eventID)
+}
+
+func (r *magicLinkRepository) DeleteByEvent(ctx context.Context, eventID string) error {
+	defer r.m.observeRepo("magic_link", "delete_by_event", time.Now())
+	return r.next.DeleteByEvent(ctx, eventID)
+}
+
+type auditRepository struct {
+	next repository.AuditRepository
+	m    *Metrics
+}
+
+// InstrumentAuditRepository times every call to r.
+func (m *Metrics) InstrumentAuditRepository(r repository.AuditRepository) repository.AuditRepository {
+	return &auditRepository{next: r, m: m}
+}
+
+func (r *auditRepository) Append(ctx context.Context, entry *model.AuditEntry) error {
+	defer r.m.observeRepo("audit", "append", time.Now())
+	return r.next.Append(ctx, entry)
+}
+
+func (r *auditRepository) Query(ctx context.Context, filter model.AuditFilter) ([]model.AuditEntry, error) {
+	defer r.m.observeRepo("audit", "query", time.Now())
+	return r.next.Query(ctx, filter)
+}
+
+type versionRepository struct {
+	next repository.VersionRepository
+	m    *Metrics
+}
+
+// InstrumentVersionRepository times every call to r.
+func (m *Metrics) InstrumentVersionRepository(r repository.VersionRepository) repository.VersionRepository {
+	return &versionRepository{next: r, m: m}
+}
+
+func (r *versionRepository) Append(ctx context.Context, v *model.EventVersion) error {
+	defer r.m.observeRepo("version", "append", time.Now())
+	return r.next.Append(ctx, v)
+}
+
+func (r *versionRepository) Get(ctx context.Context, eventID string, version int) (*model.EventVersion, error) {
+	defer r.m.observeRepo("version", "get", time.Now())
+	return r.next.Get(ctx, eventID, version)
+}
+
+func (r *versionRepository) List(ctx context.Context, eventID string) ([]model.EventVersion, error) {
+	defer r.m.observeRepo("version", "list", time.Now())
+	return r.next.List(ctx, eventID)
+}
+
+func (r *versionRepository) DeleteByEvent(ctx context.Context, eventID string) error {
+	defer r.m.observeRepo("version", "delete_by_event", time.Now())
+	return r.next.DeleteByEvent(ctx, eventID)
+}
+
+// Health checks pass through to the decorated repository.
+
+func (r *userRepository) Ping(ctx context.Context) error {
+	defer r.m.observeRepo("user", "ping", time.Now())
+	return repository.Ping(ctx, r.next)
+}
+
+func (r *eventRepository) Ping(ctx context.Context) error {
+	defer r.m.observeRepo("event", "ping", time.Now())
+	return repository.Ping(ctx, r.next)
+}
+
+func (r *availabilityRepository) Ping(ctx context.Context) error {
+	defer r.m.observeRepo("availability", "ping", time.Now())
+	return repository.Ping(ctx, r.next)
+}
+
+func (r *credentialRepository) Ping(ctx context.Context) error {
+	defer r.m.observeRepo("credential", "ping", time.Now())
+	return repository.Ping(ctx, r.next)
+}
+
+func (r *magicLinkRepository) Ping(ctx context.Context) error {
+	defer r.m.observeRepo("magic_link", "ping", time.Now())
+	return repository.Ping(ctx, r.next)
+}
+
+func (r *auditRepository) Ping(ctx context.Context) error {
+	defer r.m.observeRepo("audit", "ping", time.Now())
+	return repository.Ping(ctx, r.next)
+}
+
+func (r *versionRepository) Ping(ctx context.Context) error {
+	defer r.m.observeRepo("version", "ping", time.Now())
+	return repository.Ping(ctx, r.next)
+}
@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type User struct {
 	ID   string `json:"id"`
@@ -13,6 +16,43 @@ type Event struct {
 	DurationMin  int      `json:"duration_min"`
 	Slots        []Slot   `json:"slots"`
 	Participants []string `json:"participants"`
+	// Organizer is the user who created the event; it is set by the server.
+	// Organizer and CoOrganizers are the only users allowed to edit,
+	// finalize or delete the event.
+	Organizer    string   `json:"organizer"`
+	CoOrganizers []string `json:"co_organizers,omitempty"`
+	// Guests are external participants invited through magic links. They
+	// are not registered users and are managed by the server.
+	Guests []Guest `json:"guests,omitempty"`
+	Quorum *Quorum `json:"quorum,omitempty"`
+	// Split allows the event to run as several shorter sessions.
+	Split *SplitConfig `json:"split,omitempty"`
+	// FinalSessions holds the chosen window(s) once the event is finalized.
+	// Only finalizing the event sets it.
+	FinalSessions []Slot `json:"final_sessions,omitempty"`
+	// DeletedAt is set by the server while the event is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Quorum is the minimum attendance a window needs before it is suggested.
+// When both fields are set the stricter of the two applies.
+type Quorum struct {
+	MinCount   int `json:"min_count,omitempty"`
+	MinPercent int `json:"min_percent,omitempty"`
+}
+
+// Guest is an event participant without an account. Guest IDs carry the
+// "guest_" prefix so they never collide with registered user IDs.
+type Guest struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// SplitConfig lets an event be split into at most MaxSessions sessions of at
+// least MinSessionMin minutes each, together covering DurationMin.
+type SplitConfig struct {
+	MaxSessions   int `json:"max_sessions"`
+	MinSessionMin int `json:"min_session_min"`
 }
 
 type Slot struct {
@@ -24,9 +64,353 @@ type Availability struct {
 	EventID string `json:"event_id"`
 	UserID  string `json:"user_id"`
 	Slots   []Slot `json:"slots"`
+	// Declined marks an explicit refusal; such users are left out of
+	// suggestion attendance instead of counting as unavailable everywhere.
+	Declined  bool      `json:"declined,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type SlotSuggestion struct {
 	Slot             Slot     `json:"slot"`
 	UnavailableUsers []string `json:"unavailable_users"`
+	DeclinedUsers    []string `json:"declined_users,omitempty"`
+}
+
+// SuggestionResult is the outcome of SuggestSlots. When Viable is false
+// SuggestedSlots is empty and Reason explains why no window qualified.
+type SuggestionResult struct {
+	Viable             bool             `json:"viable"`
+	SuggestedSlots     []SlotSuggestion `json:"suggested_slots"`
+	RequiredAttendance int              `json:"required_attendance"`
+	BestAttendance     int              `json:"best_attendance"`
+	Reason             string           `json:"reason,omitempty"`
+}
+
+const (
+	StatusAvailable   = "available"
+	StatusUnavailable = "unavailable"
+	StatusNoResponse  = "no_response"
+	StatusDeclined    = "declined"
+)
+
+// ParticipantStatus describes one participant's standing for a window.
+// CoveringSlot is set for available participants and NearestAlternative,
+// when one exists, for unavailable ones.
+type ParticipantStatus struct {
+	UserID             string `json:"user_id"`
+	Status             string `json:"status"`
+	CoveringSlot       *Slot  `json:"covering_slot,omitempty"`
+	NearestAlternative *Slot  `json:"nearest_alternative,omitempty"`
+}
+
+type WindowScore struct {
+	Available          int  `json:"available"`
+	Unavailable        int  `json:"unavailable"`
+	NoResponse         int  `json:"no_response"`
+	Declined           int  `json:"declined"`
+	RequiredAttendance int  `json:"required_attendance"`
+	QuorumMet          bool `json:"quorum_met"`
+}
+
+// WindowExplanation reports why a candidate window scores the way it does.
+// Rank is 1 for windows tied with the winner and counts the distinct
+// attendance levels above it otherwise.
+type WindowExplanation struct {
+	Window           Slot                `json:"window"`
+	Participants     []ParticipantStatus `json:"participants"`
+	Score            WindowScore         `json:"score"`
+	Rank             int                 `json:"rank"`
+	CandidateWindows int                 `json:"candidate_windows"`
+	Winner           *Slot               `json:"winner,omitempty"`
+	WinnerAttendance int                 `json:"winner_attendance"`
+}
+
+type BatchScheduleRequest struct {
+	EventIDs []string `json:"event_ids"`
+}
+
+type ScheduledEvent struct {
+	EventID          string   `json:"event_id"`
+	Slot             Slot     `json:"slot"`
+	Attendees        []string `json:"attendees"`
+	UnavailableUsers []string `json:"unavailable_users"`
+}
+
+type UnplacedEvent struct {
+	EventID string `json:"event_id"`
+	Reason  string `json:"reason"`
+}
+
+// BatchSchedule assigns windows to a set of events so that no two events
+// sharing a participant overlap.
+type BatchSchedule struct {
+	Scheduled       []ScheduledEvent `json:"scheduled"`
+	Unplaced        []UnplacedEvent  `json:"unplaced"`
+	TotalAttendance int              `json:"total_attendance"`
+}
+
+type SessionSuggestion struct {
+	Sessions         []Slot   `json:"sessions"`
+	Attendees        []string `json:"attendees"`
+	UnavailableUsers []string `json:"unavailable_users"`
+}
+
+// SplitSuggestionResult mirrors SuggestionResult for events that may be
+// split into several sessions. Attendance counts users free for every session.
+type SplitSuggestionResult struct {
+	Viable             bool                `json:"viable"`
+	Suggestions        []SessionSuggestion `json:"suggestions"`
+	RequiredAttendance int                 `json:"required_attendance"`
+	BestAttendance     int                 `json:"best_attendance"`
+	Reason             string              `json:"reason,omitempty"`
+}
+
+type FinalizeRequest struct {
+	Sessions []Slot `json:"sessions"`
+}
+
+type ParticipantResponse struct {
+	UserID    string     `json:"user_id"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ResponseSummary groups an event's participants by whether they have
+// submitted availability, declined, or not answered yet.
+type ResponseSummary struct {
+	EventID   string                `json:"event_id"`
+	Responded []ParticipantResponse `json:"responded"`
+	Declined  []ParticipantResponse `json:"declined"`
+	Pending   []ParticipantResponse `json:"pending"`
+}
+
+// Credential is an API key issued to a user. Only the SHA-256 hash of the
+// key is stored; the key itself is shown once, when it is issued.
+type Credential struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	KeyHash   string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type IssuedCredential struct {
+	Credential
+	Key string `json:"key"`
+}
+
+// Registration is returned when a user signs up and carries their first key.
+type Registration struct {
+	User       *User             `json:"user"`
+	Credential *IssuedCredential `json:"credential"`
+}
+
+// MagicLink grants a guest access to a single event until it expires or is
+// revoked. The signed token itself is never stored.
+type MagicLink struct {
+	ID        string    `json:"id"`
+	EventID   string    `json:"event_id"`
+	GuestID   string    `json:"guest_id"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Revoked   bool      `json:"revoked"`
+}
+
+type IssuedMagicLink struct {
+	MagicLink
+	Token string `json:"token"`
+}
+
+// MagicLinkRequest asks for a link for an existing guest (GuestID) or for a
+// new guest called Name. TTLMin defaults to one week.
+type MagicLinkRequest struct {
+	GuestID string `json:"guest_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	TTLMin  int    `json:"ttl_min,omitempty"`
+}
+
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+	AuditImport  = "import"
+
+	EntityUser         = "user"
+	EntityEvent        = "event"
+	EntityAvailability = "availability"
+	EntityCredential   = "credential"
+	EntityArchive      = "archive"
+)
+
+// AuditEntry records one mutation. Before and After hold the JSON form of
+// the entity; Changes lists the top-level fields that differ between them.
+type AuditEntry struct {
+	Seq       int64           `json:"seq"`
+	Timestamp time.Time       `json:"timestamp"`
+	Actor     string          `json:"actor"`
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityID  string          `json:"entity_id"`
+	EventID   string          `json:"event_id,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After     json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	Changes   []FieldChange   `json:"changes,omitempty"`
+}
+
+// FieldChange holds the values of one field before and after a change. They
+// are in their JSON form and can be of any JSON type.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After  json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+}
+
+// AuditFilter selects audit entries; zero fields match everything.
+type AuditFilter struct {
+	Actor    string
+	Entity   string
+	EntityID string
+	EventID  string
+	Since    time.Time
+	Until    time.Time
+	Limit    int
+}
+
+// EventVersion is a snapshot of an event and its availability set taken
+// after a change. Event is nil for versions recorded after a deletion.
+type EventVersion struct {
+	EventID      string         `json:"event_id"`
+	Version      int            `json:"version"`
+	CreatedAt    time.Time      `json:"created_at"`
+	Actor        string         `json:"actor"`
+	Reason       string         `json:"reason"`
+	Event        *Event         `json:"event"`
+	Availability []Availability `json:"availability"`
+}
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+type AvailabilityChange struct {
+	UserID string        `json:"user_id"`
+	Change string        `json:"change"`
+	Before *Availability `json:"before,omitempty"`
+	After  *Availability `json:"after,omitempty"`
+}
+
+// VersionDiff describes what changed between two versions of an event.
+type VersionDiff struct {
+	EventID             string               `json:"event_id"`
+	From                int                  `json:"from"`
+	To                  int                  `json:"to"`
+	EventChanges        []FieldChange        `json:"event_changes"`
+	AvailabilityChanges []AvailabilityChange `json:"availability_changes"`
+}
+
+// RecordCounts is the number of stored records of each kind. Trashed events
+// are not counted.
+type RecordCounts struct {
+	Users        int `json:"users"`
+	Events       int `json:"events"`
+	Availability int `json:"availability"`
+}
+
+// Health statuses. Components are up or down; the server as a whole is ok,
+// degraded (a non-critical component is down), unavailable (a critical one
+// is down) or draining (shutting down).
+const (
+	HealthUp          = "up"
+	HealthDown        = "down"
+	HealthOK          = "ok"
+	HealthDegraded    = "degraded"
+	HealthUnavailable = "unavailable"
+	HealthDraining    = "draining"
+)
+
+// ComponentHealth is the outcome of checking one dependency.
+type ComponentHealth struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// HealthReport is the readiness of the server and of each dependency.
+type HealthReport struct {
+	Status     string            `json:"status"`
+	Version    string            `json:"version"`
+	CheckedAt  time.Time         `json:"checked_at"`
+	Components []ComponentHealth `json:"components"`
+}
+
+// ArchiveKind identifies an Archive; ArchiveVersion is the newest format
+// this server reads and the one it writes. Version 2 added credentials.
+const (
+	ArchiveKind    = "meeting-scheduler.archive"
+	ArchiveVersion = 2
+)
+
+// Archive holds every user, event, availability entry and credential of a
+// server, for backups and for moving data between servers. Trashed events
+// are included. Credentials carry only the key hash, so imported users keep
+// the API keys they had.
+type Archive struct {
+	Kind         string               `json:"kind"`
+	Version      int                  `json:"version"`
+	ExportedAt   time.Time            `json:"exported_at"`
+	Users        []User               `json:"users"`
+	Events       []Event              `json:"events"`
+	Availability []Availability       `json:"availability"`
+	Credentials  []ArchivedCredential `json:"credentials"`
+}
+
+// ArchivedCredential is a credential as archived, including the key hash the
+// API otherwise never shows.
+type ArchivedCredential struct {
+	Credential
+	KeyHash string `json:"key_hash"`
+}
+
+// Import modes. Merge adds what is missing and leaves records that differ
+// from the archive alone; replace makes events and availability match the
+// archive exactly and overwrites users that differ. Users and credentials
+// are never removed, and a credential is only ever added.
+const (
+	ImportMerge   = "merge"
+	ImportReplace = "replace"
+)
+
+// ImportReport says what an import changed, or would change in a dry run.
+type ImportReport struct {
+	Mode         string           `json:"mode"`
+	DryRun       bool             `json:"dry_run"`
+	Users        ImportCounts     `json:"users"`
+	Events       ImportCounts     `json:"events"`
+	Availability ImportCounts     `json:"availability"`
+	Credentials  ImportCounts     `json:"credentials"`
+	Conflicts    []ImportConflict `json:"conflicts"`
+}
+
+type ImportCounts struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Deleted   int `json:"deleted"`
+	// Kept counts records that differ from the archive and were left as
+	// they are.
+	Kept int `json:"kept"`
+}
+
+// ImportConflict is a record that exists on both sides with different
+// contents. Resolution is "kept" in merge mode and "replaced" in replace
+// mode.
+type ImportConflict struct {
+	Entity     string `json:"entity"`
+	ID         string `json:"id"`
+	Resolution string `json:"resolution"`
 }
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"meeting-scheduler/internal/model"
+	"sync"
+)
+
+type inMemoryAuditRepo struct {
+	entries []model.AuditEntry
+	mu      sync.RWMutex
+}
+
+func NewInMemoryAuditRepository() AuditRepository {
+	return &inMemoryAuditRepo{}
+}
+
+// Append assigns the entry the next sequence number and stores a copy.
+func (r *inMemoryAuditRepo) Append(_ context.Context, entry *model.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.Seq = int64(len(r.entries)) + 1
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+// Query returns matching entries oldest first. With a Limit only the most
+// recent matches are kept.
+func (r *inMemoryAuditRepo) Query(_ context.Context, filter model.AuditFilter) ([]model.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := []model.AuditEntry{}
+	for _, e := range r.entries {
+		if matchesAudit(e, filter) {
+			list = append(list, e)
+		}
+	}
+	if filter.Limit > 0 && len(list) > filter.Limit {
+		list = list[len(list)-filter.Limit:]
+	}
+	return list, nil
+}
+
+func matchesAudit(e model.AuditEntry, f model.AuditFilter) bool {
+	switch {
+	case f.Actor != "" && e.Actor != f.Actor:
+		return false
+	case f.Entity != "" && e.Entity != f.Entity:
+		return false
+	case f.EntityID != "" && e.EntityID != f.EntityID:
+		return false
+	case f.EventID != "" && e.EventID != f.EventID:
+		return false
+	case !f.Since.IsZero() && e.Timestamp.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && e.Timestamp.After(f.Until):
+		return false
+	}
+	return true
+}
+
+func (r *inMemoryAuditRepo) Ping(_ context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return nil
+}
@@ -0,0 +1,47 @@
+package repository_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryAuditRepo_AppendQuery(t *testing.T) {
+	repo := repository.NewInMemoryAuditRepository()
+	base := time.Date(2025, time.May, 20, 10, 0, 0, 0, time.UTC)
+
+	entries := []model.AuditEntry{
+		{Timestamp: base, Actor: "u1", Entity: model.EntityEvent, EntityID: "e1", EventID: "e1", Action: model.AuditCreate},
+		{Timestamp: base.Add(time.Minute), Actor: "u2", Entity: model.EntityAvailability, EntityID: "e1/u2", EventID: "e1", Action: model.AuditCreate},
+		{Timestamp: base.Add(2 * time.Minute), Actor: "u1", Entity: model.EntityEvent, EntityID: "e2", EventID: "e2", Action: model.AuditCreate},
+	}
+	for i := range entries {
+		require.NoError(t, repo.Append(t.Context(), &entries[i]))
+		assert.Equal(t, int64(i+1), entries[i].Seq)
+	}
+
+	entries, err := repo.Query(t.Context(), model.AuditFilter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 3)
+	entries, err = repo.Query(t.Context(), model.AuditFilter{EventID: "e1"})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	entries, err = repo.Query(t.Context(), model.AuditFilter{Actor: "u1", Entity: model.EntityEvent})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	entries, err = repo.Query(t.Context(), model.AuditFilter{Since: base.Add(time.Minute)})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	entries, err = repo.Query(t.Context(), model.AuditFilter{Until: base})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	latest, err := repo.Query(t.Context(), model.AuditFilter{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, latest, 1)
+	assert.Equal(t, "e2", latest[0].EntityID)
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"fmt"
 	"maps"
 	"meeting-scheduler/internal/model"
@@ -17,7 +18,7 @@ func NewInMemoryAvailabilityRepository() AvailabilityRepository {
 	return &inMemoryAvailabilityRepo{data: make(map[string]map[string]model.Availability)}
 }
 
-func (r *inMemoryAvailabilityRepo) Get(eventID, userID string) (model.Availability, error) {
+func (r *inMemoryAvailabilityRepo) Get(_ context.Context, eventID, userID string) (model.Availability, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	eventData, ok := r.data[eventID]
@@ -30,7 +31,7 @@ func (r *inMemoryAvailabilityRepo) Get(eventID, userID string) (model.Availabili
 	}
 	return availability, nil
 }
-func (r *inMemoryAvailabilityRepo) Create(av model.Availability) error {
+func (r *inMemoryAvailabilityRepo) Create(_ context.Context, av model.Availability) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if _, ok := r.data[av.EventID]; !ok {
@@ -43,7 +44,7 @@ func (r *inMemoryAvailabilityRepo) Create(av model.Availability) error {
 	r.data[av.EventID][av.UserID] = av
 	return nil
 }
-func (r *inMemoryAvailabilityRepo) Update(av model.Availability) error {
+func (r *inMemoryAvailabilityRepo) Update(_ context.Context, av model.Availability) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -56,18 +57,18 @@ func (r *inMemoryAvailabilityRepo) Update(av model.Availability) error {
 	r.data[av.EventID][av.UserID] = av
 	return nil
 }
-func (r *inMemoryAvailabilityRepo) GetByEvent(eventID string) map[string]model.Availability {
+func (r *inMemoryAvailabilityRepo) GetByEvent(_ context.Context, eventID string) (map[string]model.Availability, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	src, ok := r.data[eventID]
 	if !ok {
-		return nil
+		return nil, nil
 	}
 	copy := make(map[string]model.Availability, len(src))
 	maps.Copy(copy, src)
-	return copy
+	return copy, nil
 }
-func (r *inMemoryAvailabilityRepo) Delete(eventID string, userID string) error {
+func (r *inMemoryAvailabilityRepo) Delete(_ context.Context, eventID string, userID string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -80,3 +81,17 @@ func (r *inMemoryAvailabilityRepo) Delete(eventID string, userID string) error {
 	delete(r.data[eventID], userID)
 	return nil
 }
+
+// DeleteByEvent drops every availability entry of the event.
+func (r *inMemoryAvailabilityRepo) DeleteByEvent(_ context.Context, eventID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.data, eventID)
+	return nil
+}
+
+func (r *inMemoryAvailabilityRepo) Ping(_ context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return nil
+}
@@ -29,10 +29,10 @@ func TestInMemoryAvailabilityRepo_CreateGet(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability)
+	err := repo.Create(t.Context(), availability)
 	require.NoError(t, err)
 
-	gotAvailability, err := repo.Get("event1", "user1")
+	gotAvailability, err := repo.Get(t.Context(), "event1", "user1")
 	require.NoError(t, err)
 	assert.Equal(t, availability, gotAvailability)
 }
@@ -52,7 +52,7 @@ func TestInMemoryAvailabilityRepo_Update(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability)
+	err := repo.Create(t.Context(), availability)
 	require.NoError(t, err)
 
 	// Update the availability
@@ -67,10 +67,10 @@ func TestInMemoryAvailabilityRepo_Update(t *testing.T) {
 		},
 	}
 
-	err = repo.Update(updatedAvailability)
+	err = repo.Update(t.Context(), updatedAvailability)
 	require.NoError(t, err)
 
-	gotAvailability, err := repo.Get("event1", "user1")
+	gotAvailability, err := repo.Get(t.Context(), "event1", "user1")
 	require.NoError(t, err)
 	assert.Equal(t, updatedAvailability.Slots[0], gotAvailability.Slots[0])
 }
@@ -90,7 +90,7 @@ func TestInMemoryAvailabilityRepo_GetByEvent(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability1)
+	err := repo.Create(t.Context(), availability1)
 	require.NoError(t, err)
 
 	availability2 := model.Availability{
@@ -104,10 +104,11 @@ func TestInMemoryAvailabilityRepo_GetByEvent(t *testing.T) {
 		},
 	}
 
-	err = repo.Create(availability2)
+	err = repo.Create(t.Context(), availability2)
 	require.NoError(t, err)
 
-	gotAvailability := repo.GetByEvent("event1")
+	gotAvailability, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
 	assert.Len(t, gotAvailability, 2)
 }
 
@@ -115,7 +116,8 @@ func TestInMemoryAvailabilityRepo_GetByEvent(t *testing.T) {
 func TestInMemoryAvailabilityRepo_GetByEvent_NoData(t *testing.T) {
 	repo := repository.NewInMemoryAvailabilityRepository()
 
-	gotAvailability := repo.GetByEvent("event1")
+	gotAvailability, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
 	assert.Empty(t, gotAvailability)
 }
 
@@ -134,7 +136,7 @@ func TestInMemoryAvailabilityRepo_Update_NonExistent(t *testing.T) {
 		},
 	}
 
-	err := repo.Update(availability)
+	err := repo.Update(t.Context(), availability)
 	assert.EqualError(t, err, "event not found: event1")
 }
 
@@ -142,7 +144,7 @@ func TestInMemoryAvailabilityRepo_Update_NonExistent(t *testing.T) {
 func TestInMemoryAvailabilityRepo_Get_NonExistent(t *testing.T) {
 	repo := repository.NewInMemoryAvailabilityRepository()
 
-	_, err := repo.Get("event1", "user1")
+	_, err := repo.Get(t.Context(), "event1", "user1")
 	assert.EqualError(t, err, "event not found: event1")
 }
 
@@ -161,10 +163,10 @@ func TestInMemoryAvailabilityRepo_Get_NonExistentUser(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability)
+	err := repo.Create(t.Context(), availability)
 	require.NoError(t, err)
 
-	_, err = repo.Get("event1", "user2")
+	_, err = repo.Get(t.Context(), "event1", "user2")
 	assert.EqualError(t, err, "availability not found for user user2 in event event1")
 }
 
@@ -172,7 +174,8 @@ func TestInMemoryAvailabilityRepo_Get_NonExistentUser(t *testing.T) {
 func TestInMemoryAvailabilityRepo_GetByEvent_NonExistent(t *testing.T) {
 	repo := repository.NewInMemoryAvailabilityRepository()
 
-	gotAvailability := repo.GetByEvent("event1")
+	gotAvailability, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
 	assert.Empty(t, gotAvailability)
 }
 
@@ -180,7 +183,8 @@ func TestInMemoryAvailabilityRepo_GetByEvent_NonExistent(t *testing.T) {
 func TestInMemoryAvailabilityRepo_GetByEvent_Empty(t *testing.T) {
 	repo := repository.NewInMemoryAvailabilityRepository()
 
-	gotAvailability := repo.GetByEvent("event1")
+	gotAvailability, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
 	assert.Empty(t, gotAvailability)
 }
 
@@ -199,10 +203,11 @@ func TestInMemoryAvailabilityRepo_GetByEvent_OneUser(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability)
+	err := repo.Create(t.Context(), availability)
 	require.NoError(t, err)
 
-	gotAvailability := repo.GetByEvent("event1")
+	gotAvailability, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
 	assert.Len(t, gotAvailability, 1)
 }
 
@@ -221,7 +226,7 @@ func TestInMemoryAvailabilityRepo_GetByEvent_MultipleUsers(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability1)
+	err := repo.Create(t.Context(), availability1)
 	require.NoError(t, err)
 
 	availability2 := model.Availability{
@@ -235,10 +240,11 @@ func TestInMemoryAvailabilityRepo_GetByEvent_MultipleUsers(t *testing.T) {
 		},
 	}
 
-	err = repo.Create(availability2)
+	err = repo.Create(t.Context(), availability2)
 	require.NoError(t, err)
 
-	gotAvailability := repo.GetByEvent("event1")
+	gotAvailability, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
 	assert.Len(t, gotAvailability, 2)
 }
 
@@ -257,13 +263,13 @@ func TestInMemoryAvailabilityRepo_Delete(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability)
+	err := repo.Create(t.Context(), availability)
 	require.NoError(t, err)
 
-	err = repo.Delete("event1", "user1")
+	err = repo.Delete(t.Context(), "event1", "user1")
 	require.NoError(t, err)
 
-	_, err = repo.Get("event1", "user1")
+	_, err = repo.Get(t.Context(), "event1", "user1")
 	assert.EqualError(t, err, "availability not found for user user1 in event event1")
 }
 
@@ -271,7 +277,7 @@ func TestInMemoryAvailabilityRepo_Delete(t *testing.T) {
 func TestInMemoryAvailabilityRepo_Delete_NonExistent(t *testing.T) {
 	repo := repository.NewInMemoryAvailabilityRepository()
 
-	err := repo.Delete("event1", "user1")
+	err := repo.Delete(t.Context(), "event1", "user1")
 	assert.EqualError(t, err, "event not found: event1")
 }
 
@@ -290,10 +296,10 @@ func TestInMemoryAvailabilityRepo_Delete_NonExistentUser(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability)
+	err := repo.Create(t.Context(), availability)
 	require.NoError(t, err)
 
-	err = repo.Delete("event1", "user2")
+	err = repo.Delete(t.Context(), "event1", "user2")
 	assert.EqualError(t, err, "availability not found for user user2 in event event1")
 }
 
@@ -301,7 +307,7 @@ func TestInMemoryAvailabilityRepo_Delete_NonExistentUser(t *testing.T) {
 func TestInMemoryAvailabilityRepo_Delete_Empty(t *testing.T) {
 	repo := repository.NewInMemoryAvailabilityRepository()
 
-	err := repo.Delete("event1", "user1")
+	err := repo.Delete(t.Context(), "event1", "user1")
 	assert.EqualError(t, err, "event not found: event1")
 }
 
@@ -320,13 +326,14 @@ func TestInMemoryAvailabilityRepo_Delete_OneUser(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability)
+	err := repo.Create(t.Context(), availability)
 	require.NoError(t, err)
 
-	err = repo.Delete("event1", "user1")
+	err = repo.Delete(t.Context(), "event1", "user1")
 	require.NoError(t, err)
 
-	gotAvailability := repo.GetByEvent("event1")
+	gotAvailability, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
 	assert.Empty(t, gotAvailability)
 }
 
@@ -345,7 +352,7 @@ func TestInMemoryAvailabilityRepo_Delete_MultipleUsers(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability1)
+	err := repo.Create(t.Context(), availability1)
 	require.NoError(t, err)
 
 	availability2 := model.Availability{
@@ -359,13 +366,14 @@ func TestInMemoryAvailabilityRepo_Delete_MultipleUsers(t *testing.T) {
 		},
 	}
 
-	err = repo.Create(availability2)
+	err = repo.Create(t.Context(), availability2)
 	require.NoError(t, err)
 
-	err = repo.Delete("event1", "user1")
+	err = repo.Delete(t.Context(), "event1", "user1")
 	require.NoError(t, err)
 
-	gotAvailability := repo.GetByEvent("event1")
+	gotAvailability, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
 	assert.Len(t, gotAvailability, 1)
 }
 
@@ -384,7 +392,7 @@ func TestInMemoryAvailabilityRepo_Delete_All(t *testing.T) {
 		},
 	}
 
-	err := repo.Create(availability1)
+	err := repo.Create(t.Context(), availability1)
 	require.NoError(t, err)
 
 	availability2 := model.Availability{
@@ -398,15 +406,33 @@ func TestInMemoryAvailabilityRepo_Delete_All(t *testing.T) {
 		},
 	}
 
-	err = repo.Create(availability2)
+	err = repo.Create(t.Context(), availability2)
 	require.NoError(t, err)
 
-	err = repo.Delete("event1", "user1")
+	err = repo.Delete(t.Context(), "event1", "user1")
 	require.NoError(t, err)
 
-	err = repo.Delete("event1", "user2")
+	err = repo.Delete(t.Context(), "event1", "user2")
 	require.NoError(t, err)
 
-	gotAvailability := repo.GetByEvent("event1")
+	gotAvailability, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
 	assert.Empty(t, gotAvailability)
 }
+
+// TestInMemoryAvailabilityRepo_DeleteByEvent tests that DeleteByEvent removes only the given event's entries
+func TestInMemoryAvailabilityRepo_DeleteByEvent(t *testing.T) {
+	repo := repository.NewInMemoryAvailabilityRepository()
+	require.NoError(t, repo.Create(t.Context(), model.Availability{EventID: "event1", UserID: "user1"}))
+	require.NoError(t, repo.Create(t.Context(), model.Availability{EventID: "event1", UserID: "user2"}))
+	require.NoError(t, repo.Create(t.Context(), model.Availability{EventID: "event2", UserID: "user1"}))
+
+	require.NoError(t, repo.DeleteByEvent(t.Context(), "event1"))
+	entries, err := repo.GetByEvent(t.Context(), "event1")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	entries, err = repo.GetByEvent(t.Context(), "event2")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.NoError(t, repo.DeleteByEvent(t.Context(), "missing"))
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"sync"
+)
+
+type inMemoryCredentialRepo struct {
+	byID   map[string]*model.Credential
+	byHash map[string]*model.Credential
+	mu     sync.RWMutex
+}
+
+func NewInMemoryCredentialRepository() CredentialRepository {
+	return &inMemoryCredentialRepo{
+		byID:   make(map[string]*model.Credential),
+		byHash: make(map[string]*model.Credential),
+	}
+}
+
+func (r *inMemoryCredentialRepo) Create(_ context.Context, cred *model.Credential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byID[cred.ID]; ok {
+		return fmt.Errorf("credential already exists: %s", cred.ID)
+	}
+	if _, ok := r.byHash[cred.KeyHash]; ok {
+		return fmt.Errorf("credential key already in use")
+	}
+	r.byID[cred.ID] = cred
+	r.byHash[cred.KeyHash] = cred
+	return nil
+}
+func (r *inMemoryCredentialRepo) GetByKeyHash(_ context.Context, hash string) (*model.Credential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cred, ok := r.byHash[hash]
+	if !ok {
+		return nil, fmt.Errorf("credential not found")
+	}
+	return cred, nil
+}
+func (r *inMemoryCredentialRepo) ListByUser(_ context.Context, userID string) ([]*model.Credential, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := []*model.Credential{}
+	for _, cred := range r.byID {
+		if cred.UserID == userID {
+			list = append(list, cred)
+		}
+	}
+	return list, nil
+}
+func (r *inMemoryCredentialRepo) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cred, ok := r.byID[id]
+	if !ok {
+		return fmt.Errorf("credential not found: %s", id)
+	}
+	delete(r.byID, id)
+	delete(r.byHash, cred.KeyHash)
+	return nil
+}
+
+func (r *inMemoryCredentialRepo) Ping(_ context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryCredentialRepo_CreateGet(t *testing.T) {
+	repo := repository.NewInMemoryCredentialRepository()
+
+	cred := &model.Credential{ID: "k1", UserID: "u1", KeyHash: "hash1"}
+	require.NoError(t, repo.Create(t.Context(), cred))
+
+	got, err := repo.GetByKeyHash(t.Context(), "hash1")
+	require.NoError(t, err)
+	assert.Equal(t, cred, got)
+
+	_, err = repo.GetByKeyHash(t.Context(), "other")
+	assert.Error(t, err)
+}
+
+func TestInMemoryCredentialRepo_Duplicate(t *testing.T) {
+	repo := repository.NewInMemoryCredentialRepository()
+
+	require.NoError(t, repo.Create(t.Context(), &model.Credential{ID: "k1", UserID: "u1", KeyHash: "hash1"}))
+	assert.Error(t, repo.Create(t.Context(), &model.Credential{ID: "k1", UserID: "u1", KeyHash: "hash2"}))
+	assert.Error(t, repo.Create(t.Context(), &model.Credential{ID: "k2", UserID: "u1", KeyHash: "hash1"}))
+}
+
+func TestInMemoryCredentialRepo_ListByUserAndDelete(t *testing.T) {
+	repo := repository.NewInMemoryCredentialRepository()
+
+	require.NoError(t, repo.Create(t.Context(), &model.Credential{ID: "k1", UserID: "u1", KeyHash: "hash1"}))
+	require.NoError(t, repo.Create(t.Context(), &model.Credential{ID: "k2", UserID: "u1", KeyHash: "hash2"}))
+	require.NoError(t, repo.Create(t.Context(), &model.Credential{ID: "k3", UserID: "u2", KeyHash: "hash3"}))
+	keys, err := repo.ListByUser(t.Context(), "u1")
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	require.NoError(t, repo.Delete(t.Context(), "k1"))
+	keys, err = repo.ListByUser(t.Context(), "u1")
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+	_, err = repo.GetByKeyHash(t.Context(), "hash1")
+	assert.Error(t, err)
+	assert.Error(t, repo.Delete(t.Context(), "k1"))
+}
@@ -1,9 +1,11 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"meeting-scheduler/internal/model"
 	"sync"
+	"time"
 )
 
 type inMemoryEventRepo struct {
@@ -14,31 +16,31 @@ type inMemoryEventRepo struct {
 func NewInMemoryEventRepository() EventRepository {
 	return &inMemoryEventRepo{data: make(map[string]*model.Event)}
 }
-func (r *inMemoryEventRepo) Create(e *model.Event) error {
+func (r *inMemoryEventRepo) Create(_ context.Context, e *model.Event) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.data[e.ID] = e
 	return nil
 }
-func (r *inMemoryEventRepo) Get(id string) (*model.Event, error) {
+func (r *inMemoryEventRepo) Get(_ context.Context, id string) (*model.Event, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	event, ok := r.data[id]
-	if !ok {
+	if !ok || event.DeletedAt != nil {
 		return nil, errors.New("event not found")
 	}
 	return event, nil
 }
-func (r *inMemoryEventRepo) Update(e *model.Event) error {
+func (r *inMemoryEventRepo) Update(_ context.Context, e *model.Event) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if _, ok := r.data[e.ID]; !ok {
+	if existing, ok := r.data[e.ID]; !ok || existing.DeletedAt != nil {
 		return errors.New("event not found")
 	}
 	r.data[e.ID] = e
 	return nil
 }
-func (r *inMemoryEventRepo) Delete(id string) error {
+func (r *inMemoryEventRepo) Delete(_ context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if _, ok := r.data[id]; !ok {
@@ -47,22 +49,78 @@ func (r *inMemoryEventRepo) Delete(id string) error {
 	delete(r.data, id)
 	return nil
 }
-func (r *inMemoryEventRepo) List() []*model.Event {
+func (r *inMemoryEventRepo) List(_ context.Context) ([]*model.Event, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	list := []*model.Event{}
 	for _, e := range r.data {
-		list = append(list, e)
+		if e.DeletedAt == nil {
+			list = append(list, e)
+		}
 	}
-	return list
+	return list, nil
 }
-func (r *inMemoryEventRepo) AllEventIds() (map[string]struct{}, error) {
+func (r *inMemoryEventRepo) AllEventIds(_ context.Context) (map[string]struct{}, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	set := make(map[string]struct{}, len(r.data))
-	for id := range r.data {
-		set[id] = struct{}{}
+	for id, e := range r.data {
+		if e.DeletedAt == nil {
+			set[id] = struct{}{}
+		}
 	}
 	return set, nil
 }
+
+// Trash hides the event and stamps it with the time it was deleted.
+func (r *inMemoryEventRepo) Trash(_ context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.data[id]
+	if !ok || e.DeletedAt != nil {
+		return errors.New("event not found")
+	}
+	trashed := *e
+	trashed.DeletedAt = &at
+	r.data[id] = &trashed
+	return nil
+}
+func (r *inMemoryEventRepo) Restore(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.data[id]
+	if !ok || e.DeletedAt == nil {
+		return errors.New("event not found in trash")
+	}
+	restored := *e
+	restored.DeletedAt = nil
+	r.data[id] = &restored
+	return nil
+}
+func (r *inMemoryEventRepo) GetTrashed(_ context.Context, id string) (*model.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.data[id]
+	if !ok || e.DeletedAt == nil {
+		return nil, errors.New("event not found in trash")
+	}
+	return e, nil
+}
+func (r *inMemoryEventRepo) ListTrashed(_ context.Context) ([]*model.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := []*model.Event{}
+	for _, e := range r.data {
+		if e.DeletedAt != nil {
+			list = append(list, e)
+		}
+	}
+	return list, nil
+}
+
+func (r *inMemoryEventRepo) Ping(_ context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return nil
+}
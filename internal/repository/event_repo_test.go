@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestInMemoryEventRepo_CreateGet tests the Create and Get methods of InMemoryEventRepository
@@ -19,10 +20,10 @@ func TestInMemoryEventRepo_CreateGet(t *testing.T) {
 		Participants: []string{"u1", "u2"},
 	}
 
-	err := repo.Create(event)
+	err := repo.Create(t.Context(), event)
 	assert.NoError(t, err)
 
-	gotEvent, err := repo.Get("e1")
+	gotEvent, err := repo.Get(t.Context(), "e1")
 	assert.NoError(t, err)
 	assert.Equal(t, event, gotEvent)
 }
@@ -46,12 +47,12 @@ func TestInMemoryEventRepo_AllEventIds(t *testing.T) {
 		Participants: []string{"u3", "u4"},
 	}
 
-	err := repo.Create(event1)
+	err := repo.Create(t.Context(), event1)
 	assert.NoError(t, err)
-	err = repo.Create(event2)
+	err = repo.Create(t.Context(), event2)
 	assert.NoError(t, err)
 
-	allEvents, err := repo.AllEventIds()
+	allEvents, err := repo.AllEventIds(t.Context())
 	assert.NoError(t, err)
 	assert.Len(t, allEvents, 2)
 	assert.Contains(t, allEvents, "e1")
@@ -69,14 +70,14 @@ func TestInMemoryEventRepo_Update(t *testing.T) {
 		Participants: []string{"u1", "u2"},
 	}
 
-	err := repo.Create(event)
+	err := repo.Create(t.Context(), event)
 	assert.NoError(t, err)
 
 	event.Title = "Updated Meeting"
-	err = repo.Update(event)
+	err = repo.Update(t.Context(), event)
 	assert.NoError(t, err)
 
-	gotEvent, err := repo.Get("e1")
+	gotEvent, err := repo.Get(t.Context(), "e1")
 	assert.NoError(t, err)
 	assert.Equal(t, event, gotEvent)
 }
@@ -92,13 +93,13 @@ func TestInMemoryEventRepo_Delete(t *testing.T) {
 		Participants: []string{"u1", "u2"},
 	}
 
-	err := repo.Create(event)
+	err := repo.Create(t.Context(), event)
 	assert.NoError(t, err)
 
-	err = repo.Delete("e1")
+	err = repo.Delete(t.Context(), "e1")
 	assert.NoError(t, err)
 
-	gotEvent, err := repo.Get("e1")
+	gotEvent, err := repo.Get(t.Context(), "e1")
 	assert.Error(t, err)
 	assert.Nil(t, gotEvent)
 }
@@ -121,12 +122,13 @@ func TestInMemoryEventRepo_List(t *testing.T) {
 		Participants: []string{"u3", "u4"},
 	}
 
-	err := repo.Create(event1)
+	err := repo.Create(t.Context(), event1)
 	assert.NoError(t, err)
-	err = repo.Create(event2)
+	err = repo.Create(t.Context(), event2)
 	assert.NoError(t, err)
 
-	gotEvents := repo.List()
+	gotEvents, err := repo.List(t.Context())
+	require.NoError(t, err)
 	assert.Len(t, gotEvents, 2)
 	assert.Contains(t, gotEvents, event1)
 	assert.Contains(t, gotEvents, event2)
@@ -143,10 +145,10 @@ func TestInMemoryEventRepo_DeleteNonExistent(t *testing.T) {
 		Participants: []string{"u1", "u2"},
 	}
 
-	err := repo.Create(event)
+	err := repo.Create(t.Context(), event)
 	assert.NoError(t, err)
 
-	err = repo.Delete("non-existent-id")
+	err = repo.Delete(t.Context(), "non-existent-id")
 	assert.Error(t, err)
 }
 
@@ -161,11 +163,11 @@ func TestInMemoryEventRepo_UpdateNonExistent(t *testing.T) {
 		Participants: []string{"u1", "u2"},
 	}
 
-	err := repo.Create(event)
+	err := repo.Create(t.Context(), event)
 	assert.NoError(t, err)
 
 	event.ID = "non-existent-id"
-	err = repo.Update(event)
+	err = repo.Update(t.Context(), event)
 	assert.Error(t, err)
 }
 
@@ -180,10 +182,10 @@ func TestInMemoryEventRepo_GetNonExistent(t *testing.T) {
 		Participants: []string{"u1", "u2"},
 	}
 
-	err := repo.Create(event)
+	err := repo.Create(t.Context(), event)
 	assert.NoError(t, err)
 
-	gotEvent, err := repo.Get("non-existent-id")
+	gotEvent, err := repo.Get(t.Context(), "non-existent-id")
 	assert.Error(t, err)
 	assert.Nil(t, gotEvent)
 }
@@ -191,14 +193,15 @@ func TestInMemoryEventRepo_GetNonExistent(t *testing.T) {
 // TestInMemoryEventRepo_ListEmpty tests the List method of InMemoryEventRepository when no events are present
 func TestInMemoryEventRepo_ListEmpty(t *testing.T) {
 	repo := NewInMemoryEventRepository()
-	gotEvents := repo.List()
+	gotEvents, err := repo.List(t.Context())
+	require.NoError(t, err)
 	assert.Empty(t, gotEvents)
 }
 
 // TestInMemoryEventRepo_AllEventIdsEmpty tests the AllEventIds method of InMemoryEventRepository when no events are present
 func TestInMemoryEventRepo_AllEventIdsEmpty(t *testing.T) {
 	repo := NewInMemoryEventRepository()
-	allEvents, err := repo.AllEventIds()
+	allEvents, err := repo.AllEventIds(t.Context())
 	assert.NoError(t, err)
 	assert.Empty(t, allEvents)
 }
@@ -214,10 +217,59 @@ func TestInMemoryEventRepo_AllEventIdsNonExistent(t *testing.T) {
 		Participants: []string{"u1", "u2"},
 	}
 
-	err := repo.Create(event)
+	err := repo.Create(t.Context(), event)
 	assert.NoError(t, err)
 
-	allEvents, err := repo.AllEventIds()
+	allEvents, err := repo.AllEventIds(t.Context())
 	assert.NoError(t, err)
 	assert.Len(t, allEvents, 1)
 }
+
+// TestInMemoryEventRepo_TrashRestore tests that trashed events are hidden until restored
+func TestInMemoryEventRepo_TrashRestore(t *testing.T) {
+	repo := NewInMemoryEventRepository()
+	event := &model.Event{ID: "e1", Title: "Meeting", DurationMin: 60, Participants: []string{"u1"}}
+	assert.NoError(t, repo.Create(t.Context(), event))
+
+	deletedAt := time.Date(2025, time.May, 20, 9, 0, 0, 0, time.UTC)
+	assert.NoError(t, repo.Trash(t.Context(), "e1", deletedAt))
+	assert.Error(t, repo.Trash(t.Context(), "e1", deletedAt))
+
+	gotEvent, err := repo.Get(t.Context(), "e1")
+	assert.Error(t, err)
+	assert.Nil(t, gotEvent)
+	assert.Error(t, repo.Update(t.Context(), event))
+	events, err := repo.List(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	ids, _ := repo.AllEventIds(t.Context())
+	assert.Empty(t, ids)
+
+	trashed, err := repo.GetTrashed(t.Context(), "e1")
+	assert.NoError(t, err)
+	assert.Equal(t, deletedAt, *trashed.DeletedAt)
+	listed, err := repo.ListTrashed(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+	assert.Nil(t, event.DeletedAt, "trashing must not modify the caller's event")
+
+	assert.NoError(t, repo.Restore(t.Context(), "e1"))
+	assert.Error(t, repo.Restore(t.Context(), "e1"))
+	gotEvent, err = repo.Get(t.Context(), "e1")
+	assert.NoError(t, err)
+	assert.Nil(t, gotEvent.DeletedAt)
+	listed, err = repo.ListTrashed(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+}
+
+// TestInMemoryEventRepo_DeleteTrashed tests that a trashed event can be removed permanently
+func TestInMemoryEventRepo_DeleteTrashed(t *testing.T) {
+	repo := NewInMemoryEventRepository()
+	assert.NoError(t, repo.Create(t.Context(), &model.Event{ID: "e1"}))
+	assert.NoError(t, repo.Trash(t.Context(), "e1", time.Now()))
+
+	assert.NoError(t, repo.Delete(t.Context(), "e1"))
+	_, err := repo.GetTrashed(t.Context(), "e1")
+	assert.Error(t, err)
+}
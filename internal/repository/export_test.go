@@ -0,0 +1,10 @@
+package repository
+
+// Crash releases the store's files without saving, as if its process had
+// died, so a test can open the same files again.
+func (s *MemoryStore) Crash() {
+	if s.journal != nil {
+		s.journal.close()
+	}
+	s.unlock()
+}
@@ -1,26 +1,72 @@
 package repository
 
-import "meeting-scheduler/internal/model"
+import (
+	"context"
+	"meeting-scheduler/internal/model"
+	"time"
+)
+
+// Every repository method takes the caller's context first so that backends
+// can honour cancellation and deadlines. The in-memory implementations never
+// block and ignore it.
 
 type UserRepository interface {
-	Get(id string) (*model.User, error)
-	GetAll() (map[string]*model.User, error)
-	Create(user *model.User) error
+	Get(ctx context.Context, id string) (*model.User, error)
+	GetAll(ctx context.Context) (map[string]*model.User, error)
+	Create(ctx context.Context, user *model.User) error
+	Delete(ctx context.Context, id string) error
 }
 
+// EventRepository stores events. Trashed events are hidden from Get, Update,
+// List and AllEventIds until they are restored or permanently deleted.
 type EventRepository interface {
-	Create(event *model.Event) error
-	Get(id string) (*model.Event, error)
-	Update(event *model.Event) error
-	Delete(id string) error
-	List() []*model.Event
-	AllEventIds() (map[string]struct{}, error)
+	Create(ctx context.Context, event *model.Event) error
+	Get(ctx context.Context, id string) (*model.Event, error)
+	Update(ctx context.Context, event *model.Event) error
+	Delete(ctx context.Context, id string) error // permanent
+	List(ctx context.Context) ([]*model.Event, error)
+	AllEventIds(ctx context.Context) (map[string]struct{}, error)
+	Trash(ctx context.Context, id string, at time.Time) error
+	Restore(ctx context.Context, id string) error
+	GetTrashed(ctx context.Context, id string) (*model.Event, error)
+	ListTrashed(ctx context.Context) ([]*model.Event, error)
 }
 
 type AvailabilityRepository interface {
-	Get(eventID, userID string) (model.Availability, error)
-	Create(av model.Availability) error
-	Update(av model.Availability) error
-	GetByEvent(eventID string) map[string]model.Availability // user -> Availability
-	Delete(eventID, userID string) error
+	Get(ctx context.Context, eventID, userID string) (model.Availability, error)
+	Create(ctx context.Context, av model.Availability) error
+	Update(ctx context.Context, av model.Availability) error
+	GetByEvent(ctx context.Context, eventID string) (map[string]model.Availability, error) // user -> Availability
+	Delete(ctx context.Context, eventID, userID string) error
+	DeleteByEvent(ctx context.Context, eventID string) error
+}
+
+type CredentialRepository interface {
+	Create(ctx context.Context, cred *model.Credential) error
+	GetByKeyHash(ctx context.Context, hash string) (*model.Credential, error)
+	ListByUser(ctx context.Context, userID string) ([]*model.Credential, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type MagicLinkRepository interface {
+	Create(ctx context.Context, link *model.MagicLink) error
+	Get(ctx context.Context, id string) (*model.MagicLink, error)
+	Update(ctx context.Context, link *model.MagicLink) error
+	ListByEvent(ctx context.Context, eventID string) ([]*model.MagicLink, error)
+	DeleteByEvent(ctx context.Context, eventID string) error
+}
+
+// AuditRepository is an append-only store; entries can never be changed or
+// removed once written.
+type AuditRepository interface {
+	Append(ctx context.Context, entry *model.AuditEntry) error
+	Query(ctx context.Context, filter model.AuditFilter) ([]model.AuditEntry, error)
+}
+
+// VersionRepository keeps the numbered snapshots of each event.
+type VersionRepository interface {
+	Append(ctx context.Context, v *model.EventVersion) error
+	Get(ctx context.Context, eventID string, version int) (*model.EventVersion, error)
+	List(ctx context.Context, eventID string) ([]model.EventVersion, error)
+	DeleteByEvent(ctx context.Context, eventID string) error
 }
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"meeting-scheduler/internal/model"
+	"os"
+	"time"
+)
+
+// Journal operations, named after the repository and method they replay.
+const (
+	opUserCreate                = "user.create"
+	opUserDelete                = "user.delete"
+	opEventCreate               = "event.create"
+	opEventUpdate               = "event.update"
+	opEventDelete               = "event.delete"
+	opEventTrash                = "event.trash"
+	opEventRestore              = "event.restore"
+	opAvailabilityCreate        = "availability.create"
+	opAvailabilityUpdate        = "availability.update"
+	opAvailabilityDelete        = "availability.delete"
+	opAvailabilityDeleteByEvent = "availability.delete_by_event"
+	opCredentialCreate          = "credential.create"
+	opCredentialDelete          = "credential.delete"
+	opMagicLinkCreate           = "magic_link.create"
+	opMagicLinkUpdate           = "magic_link.update"
+	opMagicLinkDeleteByEvent    = "magic_link.delete_by_event"
+	opAuditAppend               = "audit.append"
+	opVersionAppend             = "version.append"
+	opVersionDeleteByEvent      = "version.delete_by_event"
+)
+
+// journalRecord is one line of the journal. Seq increases by one per record
+// and is never reset, so a snapshot can say which records it already holds.
+type journalRecord struct {
+	Seq  int64           `json:"seq"`
+	Op   string          `json:"op"`
+	Data json.RawMessage `json:"data"`
+}
+
+// journalKey is the payload of operations that only identify a record.
+type journalKey struct {
+	ID      string    `json:"id,omitempty"`
+	EventID string    `json:"event_id,omitempty"`
+	UserID  string    `json:"user_id,omitempty"`
+	At      time.Time `json:"at,omitzero"`
+}
+
+// journal is an append-only file of JSON lines, each synced to disk before
+// the write it records is applied.
+type journal struct {
+	f    *os.File
+	seq  int64
+	size int64
+}
+
+// readJournal returns the complete records in the journal at path and the
+// offset just past the last of them. A final line without its newline is a
+// record whose write was cut short by a crash; it was never acknowledged and
+// is dropped. Any other unreadable line means the journal is corrupt.
+func readJournal(path string) ([]journalRecord, int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading journal: %w", err)
+	}
+	var (
+		records []journalRecord
+		offset  int64
+	)
+	r := bufio.NewReader(bytes.NewReader(data))
+	for line := 1; ; line++ {
+		b, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			return records, offset, nil // a torn final record, if any, is dropped
+		}
+		var rec journalRecord
+		if jerr := json.Unmarshal(b, &rec); jerr != nil || rec.Op == "" {
+			return nil, 0, fmt.Errorf("journal %s is corrupt at line %d", path, line)
+		}
+		records = append(records, rec)
+		offset += int64(len(b))
+	}
+}
+
+// openJournal opens the journal at path for appending after its last
+// complete record, cutting off a torn one, and continues numbering at seq.
+func openJournal(path string, end, seq int64) (*journal, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+	if err := f.Truncate(end); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("truncating journal: %w", err)
+	}
+	if _, err := f.Seek(end, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("opening journal: %w", err)
+	}
+	return &journal{f: f, seq: seq, size: end}, nil
+}
+
+func (j *journal) append(op string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(journalRecord{Seq: j.seq + 1, Op: op, Data: payload})
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err := j.f.Write(line); err != nil {
+		return err
+	}
+	if err := j.f.Sync(); err != nil {
+		return err
+	}
+	j.seq++
+	j.size += int64(len(line))
+	return nil
+}
+
+// reset empties the journal once a snapshot holds everything in it.
+func (j *journal) reset() error {
+	if err := j.f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := j.f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	j.size = 0
+	return j.f.Sync()
+}
+
+func (j *journal) close() error {
+	return j.f.Close()
+}
+
+// replay applies rec to the store's repositories. Writes that failed when
+// they were first made fail the same way again, so their errors are
+// ignored; only a record that cannot be decoded is an error.
+func (s *MemoryStore) replay(rec journalRecord) error {
+	ctx := context.Background()
+	decode := func(v any) error {
+		if err := json.Unmarshal(rec.Data, v); err != nil {
+			return fmt.Errorf("journal record %d (%s): %w", rec.Seq, rec.Op, err)
+		}
+		return nil
+	}
+	var (
+		key   journalKey
+		user  model.User
+		ev    model.Event
+		av    model.Availability
+		cred  StoredCredential
+		link  model.MagicLink
+		entry model.AuditEntry
+		v     model.EventVersion
+	)
+	switch rec.Op {
+	case opUserCreate:
+		if err := decode(&user); err != nil {
+			return err
+		}
+		s.users.Create(ctx, &user)
+	case opUserDelete:
+		if err := decode(&key); err != nil {
+			return err
+		}
+		s.users.Delete(ctx, key.ID)
+	case opEventCreate, opEventUpdate:
+		if err := decode(&ev); err != nil {
+			return err
+		}
+		if rec.Op == opEventCreate {
+			s.events.Create(ctx, &ev)
+		} else {
+			s.events.Update(ctx, &ev)
+		}
+	case opEventDelete, opEventTrash, opEventRestore:
+		if err := decode(&key); err != nil {
+			return err
+		}
+		switch rec.Op {
+		case opEventDelete:
+			s.events.Delete(ctx, key.ID)
+		case opEventTrash:
+			s.events.Trash(ctx, key.ID, key.At)
+		default:
+			s.events.Restore(ctx, key.ID)
+		}
+	case opAvailabilityCreate, opAvailabilityUpdate:
+		if err := decode(&av); err != nil {
+			return err
+		}
+		if rec.Op == opAvailabilityCreate {
+			s.availability.Create(ctx, av)
+		} else {
+			s.availability.Update(ctx, av)
+		}
+	case opAvailabilityDelete, opAvailabilityDeleteByEvent:
+		if err := decode(&key); err != nil {
+			return err
+		}
+		if rec.Op == opAvailabilityDelete {
+			s.availability.Delete(ctx, key.EventID, key.UserID)
+		} else {
+			s.availability.DeleteByEvent(ctx, key.EventID)
+		}
+	case opCredentialCreate:
+		if err := decode(&cred); err != nil {
+			return err
+		}
+		c := cred.Credential
+		c.KeyHash = cred.KeyHash
+		s.credentials.Create(ctx, &c)
+	case opCredentialDelete:
+		if err := decode(&key); err != nil {
+			return err
+		}
+		s.credentials.Delete(ctx, key.ID)
+	case opMagicLinkCreate, opMagicLinkUpdate:
+		if err := decode(&link); err != nil {
+			return err
+		}
+		if rec.Op == opMagicLinkCreate {
+			s.links.Create(ctx, &link)
+		} else {
+			s.links.Update(ctx, &link)
+		}
+	case opMagicLinkDeleteByEvent:
+		if err := decode(&key); err != nil {
+			return err
+		}
+		s.links.DeleteByEvent(ctx, key.EventID)
+	case opAuditAppend:
+		if err := decode(&entry); err != nil {
+			return err
+		}
+		s.audit.Append(ctx, &entry)
+	case opVersionAppend:
+		if err := decode(&v); err != nil {
+			return err
+		}
+		s.versions.Append(ctx, &v)
+	case opVersionDeleteByEvent:
+		if err := decode(&key); err != nil {
+			return err
+		}
+		s.versions.DeleteByEvent(ctx, key.EventID)
+	default:
+		return fmt.Errorf("journal record %d has unknown operation %q", rec.Seq, rec.Op)
+	}
+	return nil
+}
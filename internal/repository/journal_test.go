@@ -0,0 +1,148 @@
+package repository_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// journaledStore opens a store with a journal that never compacts on its
+// own, in a fresh directory, and returns it with its snapshot and journal
+// paths.
+func journaledStore(t *testing.T) (*repository.MemoryStore, string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	snapshot, journal := filepath.Join(dir, "data.json"), filepath.Join(dir, "data.journal")
+	store, err := repository.OpenMemoryStore(snapshot, repository.WithJournal(journal, 1<<30))
+	require.NoError(t, err)
+	t.Cleanup(store.Crash)
+	return store, snapshot, journal
+}
+
+func reopen(t *testing.T, snapshot, journal string) *repository.MemoryStore {
+	t.Helper()
+	store, err := repository.OpenMemoryStore(snapshot, repository.WithJournal(journal, 1<<30))
+	require.NoError(t, err)
+	t.Cleanup(store.Crash)
+	return store
+}
+
+func TestJournal_ReplaysWritesAfterCrash(t *testing.T) {
+	store, snapshot, journal := journaledStore(t)
+	ctx := t.Context()
+	require.NoError(t, store.Users().Create(ctx, &model.User{ID: "u1", Name: "Alice"}))
+	require.NoError(t, store.Users().Create(ctx, &model.User{ID: "u2", Name: "Bob"}))
+	require.NoError(t, store.Users().Delete(ctx, "u2"))
+	require.NoError(t, store.Events().Create(ctx, &model.Event{ID: "e1", Title: "Draft"}))
+	require.NoError(t, store.Events().Update(ctx, &model.Event{ID: "e1", Title: "Planning"}))
+	require.NoError(t, store.Availability().Create(ctx, model.Availability{EventID: "e1", UserID: "u1"}))
+	require.NoError(t, store.Availability().Delete(ctx, "e1", "u1"))
+	require.NoError(t, store.MagicLinks().Create(ctx, &model.MagicLink{ID: "l1", EventID: "e1"}))
+	require.NoError(t, store.MagicLinks().DeleteByEvent(ctx, "e1"))
+	require.Error(t, store.Events().Update(ctx, &model.Event{ID: "missing"}), "failed writes are journaled too")
+	// No Close: the process dies here and no snapshot is written.
+	store.Crash()
+
+	recovered := reopen(t, snapshot, journal)
+	user, err := recovered.Users().Get(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", user.Name)
+	user, err = recovered.Users().Get(ctx, "u2")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+	event, err := recovered.Events().Get(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Planning", event.Title)
+	entries, err := recovered.Availability().GetByEvent(ctx, "e1")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	links, err := recovered.MagicLinks().ListByEvent(ctx, "e1")
+	require.NoError(t, err)
+	assert.Empty(t, links)
+}
+
+func TestJournal_DropsTruncatedFinalRecord(t *testing.T) {
+	store, snapshot, journal := journaledStore(t)
+	ctx := t.Context()
+	require.NoError(t, store.Users().Create(ctx, &model.User{ID: "u1", Name: "Alice"}))
+	require.NoError(t, store.Users().Create(ctx, &model.User{ID: "u2", Name: "Bob"}))
+	complete, err := os.ReadFile(journal)
+	require.NoError(t, err)
+
+	// A crash in the middle of writing the third record.
+	f, err := os.OpenFile(journal, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"seq":3,"op":"user.create","data":{"id":"u3","na`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	store.Crash()
+
+	recovered := reopen(t, snapshot, journal)
+	users, err := recovered.Users().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.NotContains(t, users, "u3")
+	onDisk, err := os.ReadFile(journal)
+	require.NoError(t, err)
+	assert.Equal(t, complete, onDisk, "the torn record is cut off")
+
+	// Writing after recovery continues a valid journal.
+	require.NoError(t, recovered.Users().Create(ctx, &model.User{ID: "u3", Name: "Carol"}))
+	recovered.Crash()
+	users, err = reopen(t, snapshot, journal).Users().GetAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, users, 3)
+}
+
+func TestJournal_RejectsCorruptRecord(t *testing.T) {
+	store, snapshot, journal := journaledStore(t)
+	require.NoError(t, store.Users().Create(t.Context(), &model.User{ID: "u1"}))
+	data, err := os.ReadFile(journal)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(journal, append([]byte("garbage\n"), data...), 0o600))
+	store.Crash()
+
+	_, err = repository.OpenMemoryStore(snapshot, repository.WithJournal(journal, 1<<30))
+	assert.ErrorContains(t, err, "is corrupt at line 1")
+}
+
+func TestJournal_CompactsIntoSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	snapshot, journal := filepath.Join(dir, "data.json"), filepath.Join(dir, "data.journal")
+	store, err := repository.OpenMemoryStore(snapshot, repository.WithJournal(journal, 1))
+	require.NoError(t, err)
+	require.NoError(t, store.Users().Create(t.Context(), &model.User{ID: "u1", Name: "Alice"}))
+	store.Crash()
+
+	info, err := os.Stat(journal)
+	require.NoError(t, err)
+	assert.Zero(t, info.Size(), "the journal is emptied once compacted")
+	_, err = os.Stat(snapshot)
+	require.NoError(t, err)
+
+	user, err := reopen(t, snapshot, journal).Users().Get(t.Context(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", user.Name)
+}
+
+func TestJournal_SkipsRecordsAlreadyInSnapshot(t *testing.T) {
+	store, snapshot, journal := journaledStore(t)
+	ctx := t.Context()
+	require.NoError(t, store.Audit().Append(ctx, &model.AuditEntry{Actor: "u1", Action: model.AuditCreate}))
+	stale, err := os.ReadFile(journal)
+	require.NoError(t, err)
+	require.NoError(t, store.Close(ctx))
+
+	// A crash after the snapshot was written but before the journal was
+	// emptied leaves the old records behind.
+	require.NoError(t, os.WriteFile(journal, stale, 0o600))
+
+	entries, err := reopen(t, snapshot, journal).Audit().Query(ctx, model.AuditFilter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "the append must not be applied twice")
+}
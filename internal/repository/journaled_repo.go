@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"meeting-scheduler/internal/model"
+	"time"
+)
+
+// The types below journal every write to a MemoryStore repository before
+// applying it. Reads go straight to the embedded repository.
+
+type journaledUserRepo struct {
+	*inMemoryUserRepo
+	s *MemoryStore
+}
+
+func (r journaledUserRepo) Create(ctx context.Context, user *model.User) error {
+	return r.s.write(opUserCreate, user, func() error { return r.inMemoryUserRepo.Create(ctx, user) })
+}
+
+func (r journaledUserRepo) Delete(ctx context.Context, id string) error {
+	return r.s.write(opUserDelete, journalKey{ID: id}, func() error { return r.inMemoryUserRepo.Delete(ctx, id) })
+}
+
+type journaledEventRepo struct {
+	*inMemoryEventRepo
+	s *MemoryStore
+}
+
+func (r journaledEventRepo) Create(ctx context.Context, event *model.Event) error {
+	return r.s.write(opEventCreate, event, func() error { return r.inMemoryEventRepo.Create(ctx, event) })
+}
+
+func (r journaledEventRepo) Update(ctx context.Context, event *model.Event) error {
+	return r.s.write(opEventUpdate, event, func() error { return r.inMemoryEventRepo.Update(ctx, event) })
+}
+
+func (r journaledEventRepo) Delete(ctx context.Context, id string) error {
+	return r.s.write(opEventDelete, journalKey{ID: id}, func() error { return r.inMemoryEventRepo.Delete(ctx, id) })
+}
+
+func (r journaledEventRepo) Trash(ctx context.Context, id string, at time.Time) error {
+	return r.s.write(opEventTrash, journalKey{ID: id, At: at}, func() error { return r.inMemoryEventRepo.Trash(ctx, id, at) })
+}
+
+func (r journaledEventRepo) Restore(ctx context.Context, id string) error {
+	return r.s.write(opEventRestore, journalKey{ID: id}, func() error { return r.inMemoryEventRepo.Restore(ctx, id) })
+}
+
+type journaledAvailabilityRepo struct {
+	*inMemoryAvailabilityRepo
+	s *MemoryStore
+}
+
+func (r journaledAvailabilityRepo) Create(ctx context.Context, av model.Availability) error {
+	return r.s.write(opAvailabilityCreate, av, func() error { return r.inMemoryAvailabilityRepo.Create(ctx, av) })
+}
+
+func (r journaledAvailabilityRepo) Update(ctx context.Context, av model.Availability) error {
+	return r.s.write(opAvailabilityUpdate, av, func() error { return r.inMemoryAvailabilityRepo.Update(ctx, av) })
+}
+
+func (r journaledAvailabilityRepo) Delete(ctx context.Context, eventID, userID string) error {
+	return r.s.write(opAvailabilityDelete, journalKey{EventID: eventID, UserID: userID}, func() error {
+		return r.inMemoryAvailabilityRepo.Delete(ctx, eventID, userID)
+	})
+}
+
+func (r journaledAvailabilityRepo) DeleteByEvent(ctx context.Context, eventID string) error {
+	return r.s.write(opAvailabilityDeleteByEvent, journalKey{EventID: eventID}, func() error {
+		return r.inMemoryAvailabilityRepo.DeleteByEvent(ctx, eventID)
+	})
+}
+
+type journaledCredentialRepo struct {
+	*inMemoryCredentialRepo
+	s *MemoryStore
+}
+
+func (r journaledCredentialRepo) Create(ctx context.Context, cred *model.Credential) error {
+	stored := StoredCredential{Credential: *cred, KeyHash: cred.KeyHash}
+	return r.s.write(opCredentialCreate, stored, func() error { return r.inMemoryCredentialRepo.Create(ctx, cred) })
+}
+
+func (r journaledCredentialRepo) Delete(ctx context.Context, id string) error {
+	return r.s.write(opCredentialDelete, journalKey{ID: id}, func() error { return r.inMemoryCredentialRepo.Delete(ctx, id) })
+}
+
+type journaledMagicLinkRepo struct {
+	*inMemoryMagicLinkRepo
+	s *MemoryStore
+}
+
+func (r journaledMagicLinkRepo) Create(ctx context.Context, link *model.MagicLink) error {
+	return r.s.write(opMagicLinkCreate, link, func() error { return r.inMemoryMagicLinkRepo.Create(ctx, link) })
+}
+
+func (r journaledMagicLinkRepo) Update(ctx context.Context, link *model.MagicLink) error {
+	return r.s.write(opMagicLinkUpdate, link, func() error { return r.inMemoryMagicLinkRepo.Update(ctx, link) })
+}
+
+func (r journaledMagicLinkRepo) DeleteByEvent(ctx context.Context, eventID string) error {
+	return r.s.write(opMagicLinkDeleteByEvent, journalKey{EventID: eventID}, func() error {
+		return r.inMemoryMagicLinkRepo.DeleteByEvent(ctx, eventID)
+	})
+}
+
+type journaledAuditRepo struct {
+	*inMemoryAuditRepo
+	s *MemoryStore
+}
+
+func (r journaledAuditRepo) Append(ctx context.Context, entry *model.AuditEntry) error {
+	return r.s.write(opAuditAppend, entry, func() error { return r.inMemoryAuditRepo.Append(ctx, entry) })
+}
+
+type journaledVersionRepo struct {
+	*inMemoryVersionRepo
+	s *MemoryStore
+}
+
+func (r journaledVersionRepo) Append(ctx context.Context, v *model.EventVersion) error {
+	return r.s.write(opVersionAppend, v, func() error { return r.inMemoryVersionRepo.Append(ctx, v) })
+}
+
+func (r journaledVersionRepo) DeleteByEvent(ctx context.Context, eventID string) error {
+	return r.s.write(opVersionDeleteByEvent, journalKey{EventID: eventID}, func() error {
+		return r.inMemoryVersionRepo.DeleteByEvent(ctx, eventID)
+	})
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+// Closer is implemented by repositories that hold resources or buffered
+// writes which must be released or flushed before the process exits. The
+// in-memory repositories hold neither.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// HealthChecker is implemented by repositories that can tell whether their
+// backing store is usable. The in-memory repositories report healthy once
+// their lock can be taken, which catches a writer that never released it.
+type HealthChecker interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks repo if it implements HealthChecker and otherwise reports it
+// healthy. Decorators use it to pass health checks through.
+func Ping(ctx context.Context, repo any) error {
+	if h, ok := repo.(HealthChecker); ok {
+		return h.Ping(ctx)
+	}
+	return nil
+}
+
+// Close closes, in order, each of repos that implements Closer and returns
+// the errors of those that failed. ctx bounds how long closing may take.
+func Close(ctx context.Context, repos ...any) error {
+	var errs []error
+	for _, r := range repos {
+		if c, ok := r.(Closer); ok {
+			if err := c.Close(ctx); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,40 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"meeting-scheduler/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type closer struct {
+	name   string
+	err    error
+	closed *[]string
+}
+
+func (c closer) Close(context.Context) error {
+	*c.closed = append(*c.closed, c.name)
+	return c.err
+}
+
+func TestClose(t *testing.T) {
+	var closed []string
+	failure := errors.New("flush failed")
+
+	err := repository.Close(t.Context(),
+		closer{name: "events", closed: &closed},
+		repository.NewInMemoryUserRepository(),
+		closer{name: "availability", err: failure, closed: &closed},
+		closer{name: "audit", closed: &closed},
+	)
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, []string{"events", "availability", "audit"}, closed, "a failure must not stop the others closing")
+}
+
+func TestPing(t *testing.T) {
+	assert.NoError(t, repository.Ping(t.Context(), repository.NewInMemoryEventRepository()))
+	assert.NoError(t, repository.Ping(t.Context(), struct{}{}), "repositories without a check count as healthy")
+}
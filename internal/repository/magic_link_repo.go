@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"sync"
+)
+
+type inMemoryMagicLinkRepo struct {
+	data map[string]*model.MagicLink
+	mu   sync.RWMutex
+}
+
+func NewInMemoryMagicLinkRepository() MagicLinkRepository {
+	return &inMemoryMagicLinkRepo{data: make(map[string]*model.MagicLink)}
+}
+
+func (r *inMemoryMagicLinkRepo) Create(_ context.Context, link *model.MagicLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.data[link.ID]; ok {
+		return fmt.Errorf("magic link already exists: %s", link.ID)
+	}
+	r.data[link.ID] = link
+	return nil
+}
+func (r *inMemoryMagicLinkRepo) Get(_ context.Context, id string) (*model.MagicLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	link, ok := r.data[id]
+	if !ok {
+		return nil, fmt.Errorf("magic link not found: %s", id)
+	}
+	return link, nil
+}
+func (r *inMemoryMagicLinkRepo) Update(_ context.Context, link *model.MagicLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.data[link.ID]; !ok {
+		return fmt.Errorf("magic link not found: %s", link.ID)
+	}
+	r.data[link.ID] = link
+	return nil
+}
+func (r *inMemoryMagicLinkRepo) ListByEvent(_ context.Context, eventID string) ([]*model.MagicLink, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := []*model.MagicLink{}
+	for _, link := range r.data {
+		if link.EventID == eventID {
+			list = append(list, link)
+		}
+	}
+	return list, nil
+}
+
+// DeleteByEvent drops every magic link issued for the event.
+func (r *inMemoryMagicLinkRepo) DeleteByEvent(_ context.Context, eventID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, link := range r.data {
+		if link.EventID == eventID {
+			delete(r.data, id)
+		}
+	}
+	return nil
+}
+
+func (r *inMemoryMagicLinkRepo) Ping(_ context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return nil
+}
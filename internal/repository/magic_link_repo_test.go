@@ -0,0 +1,65 @@
+package repository_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryMagicLinkRepo_CreateGetUpdate(t *testing.T) {
+	repo := repository.NewInMemoryMagicLinkRepository()
+
+	link := &model.MagicLink{ID: "l1", EventID: "e1", GuestID: "guest_1"}
+	require.NoError(t, repo.Create(t.Context(), link))
+	assert.Error(t, repo.Create(t.Context(), link))
+
+	got, err := repo.Get(t.Context(), "l1")
+	require.NoError(t, err)
+	assert.Equal(t, link, got)
+
+	revoked := *link
+	revoked.Revoked = true
+	require.NoError(t, repo.Update(t.Context(), &revoked))
+	got, err = repo.Get(t.Context(), "l1")
+	require.NoError(t, err)
+	assert.True(t, got.Revoked)
+
+	assert.Error(t, repo.Update(t.Context(), &model.MagicLink{ID: "missing"}))
+}
+
+func TestInMemoryMagicLinkRepo_ListByEvent(t *testing.T) {
+	repo := repository.NewInMemoryMagicLinkRepository()
+
+	require.NoError(t, repo.Create(t.Context(), &model.MagicLink{ID: "l1", EventID: "e1"}))
+	require.NoError(t, repo.Create(t.Context(), &model.MagicLink{ID: "l2", EventID: "e1"}))
+	require.NoError(t, repo.Create(t.Context(), &model.MagicLink{ID: "l3", EventID: "e2"}))
+
+	links, err := repo.ListByEvent(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Len(t, links, 2)
+	links, err = repo.ListByEvent(t.Context(), "e3")
+	require.NoError(t, err)
+	assert.Empty(t, links)
+}
+
+func TestInMemoryMagicLinkRepo_DeleteByEvent(t *testing.T) {
+	repo := repository.NewInMemoryMagicLinkRepository()
+
+	require.NoError(t, repo.Create(t.Context(), &model.MagicLink{ID: "l1", EventID: "e1"}))
+	require.NoError(t, repo.Create(t.Context(), &model.MagicLink{ID: "l2", EventID: "e1"}))
+	require.NoError(t, repo.Create(t.Context(), &model.MagicLink{ID: "l3", EventID: "e2"}))
+
+	require.NoError(t, repo.DeleteByEvent(t.Context(), "e1"))
+	links, err := repo.ListByEvent(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Empty(t, links)
+	links, err = repo.ListByEvent(t.Context(), "e2")
+	require.NoError(t, err)
+	assert.Len(t, links, 1)
+	_, err = repo.Get(t.Context(), "l1")
+	assert.Error(t, err)
+	assert.NoError(t, repo.DeleteByEvent(t.Context(), "missing"))
+}
@@ -0,0 +1,492 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"meeting-scheduler/internal/model"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SnapshotFormat is the format version written to new snapshots. Bump it
+// whenever the snapshot layout or a stored model changes incompatibly, and
+// register a migration from the previous version in snapshotMigrations.
+const SnapshotFormat = 1
+
+// snapshotMigrations upgrade a decoded snapshot from the format version they
+// are keyed by to the next one.
+var snapshotMigrations = map[int]func(map[string]json.RawMessage) error{}
+
+// ErrStoreLocked is returned by OpenMemoryStore when another process has the
+// same snapshot open.
+var ErrStoreLocked = errors.New("store is in use by another process")
+
+// Snapshot is the on-disk form of a MemoryStore. Trashed events are kept.
+type Snapshot struct {
+	FormatVersion int       `json:"format_version"`
+	TakenAt       time.Time `json:"taken_at"`
+	// JournalSeq is the sequence number of the last journal record the
+	// snapshot holds; replay skips records up to it.
+	JournalSeq   int64                `json:"journal_seq,omitempty"`
+	Users        []model.User         `json:"users"`
+	Events       []model.Event        `json:"events"`
+	Availability []model.Availability `json:"availability"`
+	Credentials  []StoredCredential   `json:"credentials"`
+	MagicLinks   []model.MagicLink    `json:"magic_links"`
+	Audit        []model.AuditEntry   `json:"audit"`
+	Versions     []model.EventVersion `json:"versions"`
+}
+
+// StoredCredential is a credential as persisted, including the key hash the
+// API never shows.
+type StoredCredential struct {
+	model.Credential
+	KeyHash string `json:"key_hash"`
+}
+
+// MemoryStore holds one in-memory repository of each kind and saves and
+// restores them together. Each repository is captured under its own lock,
+// so a snapshot taken during writes may be a few writes apart between
+// repositories.
+type MemoryStore struct {
+	users        *inMemoryUserRepo
+	events       *inMemoryEventRepo
+	availability *inMemoryAvailabilityRepo
+	credentials  *inMemoryCredentialRepo
+	links        *inMemoryMagicLinkRepo
+	audit        *inMemoryAuditRepo
+	versions     *inMemoryVersionRepo
+
+	path   string
+	lock   *os.File
+	saveMu sync.Mutex
+
+	// With a journal, writeMu is held from journaling a write until it has
+	// been applied, so the journal lists writes in the order they happened
+	// and a snapshot never falls between the two.
+	journal     *journal
+	journalPath string
+	compactAt   int64
+	writeMu     sync.Mutex
+	// savedSeq is the last journal record the snapshot on disk holds.
+	savedSeq int64
+}
+
+// StoreOption configures OpenMemoryStore.
+type StoreOption func(*MemoryStore)
+
+// WithJournal records every write in an append-only journal at path, synced
+// to disk before the write is applied and replayed over the snapshot on the
+// next start. Once the journal grows past compactAt bytes it is compacted
+// into a new snapshot.
+func WithJournal(path string, compactAt int64) StoreOption {
+	return func(s *MemoryStore) {
+		s.journalPath = path
+		s.compactAt = compactAt
+	}
+}
+
+// NewMemoryStore returns an empty store that is never saved.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:        NewInMemoryUserRepository().(*inMemoryUserRepo),
+		events:       NewInMemoryEventRepository().(*inMemoryEventRepo),
+		availability: NewInMemoryAvailabilityRepository().(*inMemoryAvailabilityRepo),
+		credentials:  NewInMemoryCredentialRepository().(*inMemoryCredentialRepo),
+		links:        NewInMemoryMagicLinkRepository().(*inMemoryMagicLinkRepo),
+		audit:        NewInMemoryAuditRepository().(*inMemoryAuditRepo),
+		versions:     NewInMemoryVersionRepository().(*inMemoryVersionRepo),
+	}
+}
+
+// OpenMemoryStore returns a store backed by the snapshot at path, loading it
+// if it exists and replaying the journal, if any, on top. Save and Close
+// write back to path. The store stays locked against other processes until
+// it is closed.
+func OpenMemoryStore(path string, opts ...StoreOption) (_ *MemoryStore, err error) {
+	s := NewMemoryStore()
+	s.path = path
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.lock, err = lockStore(path); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			s.unlock()
+		}
+	}()
+	var seq int64
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	default:
+		snap, err := DecodeSnapshot(data)
+		if err != nil {
+			return nil, fmt.Errorf("loading snapshot %s: %w", path, err)
+		}
+		s.Restore(snap)
+		seq = snap.JournalSeq
+	}
+	s.savedSeq = seq
+	if s.journalPath == "" {
+		return s, nil
+	}
+
+	records, end, err := readJournal(s.journalPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		if rec.Seq <= seq {
+			continue // already in the snapshot
+		}
+		if err := s.replay(rec); err != nil {
+			return nil, err
+		}
+		seq = rec.Seq
+	}
+	if s.journal, err = openJournal(s.journalPath, end, seq); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *MemoryStore) Users() UserRepository {
+	if s.journal != nil {
+		return journaledUserRepo{s.users, s}
+	}
+	return s.users
+}
+
+func (s *MemoryStore) Events() EventRepository {
+	if s.journal != nil {
+		return journaledEventRepo{s.events, s}
+	}
+	return s.events
+}
+
+func (s *MemoryStore) Availability() AvailabilityRepository {
+	if s.journal != nil {
+		return journaledAvailabilityRepo{s.availability, s}
+	}
+	return s.availability
+}
+
+func (s *MemoryStore) Credentials() CredentialRepository {
+	if s.journal != nil {
+		return journaledCredentialRepo{s.credentials, s}
+	}
+	return s.credentials
+}
+
+func (s *MemoryStore) MagicLinks() MagicLinkRepository {
+	if s.journal != nil {
+		return journaledMagicLinkRepo{s.links, s}
+	}
+	return s.links
+}
+
+func (s *MemoryStore) Audit() AuditRepository {
+	if s.journal != nil {
+		return journaledAuditRepo{s.audit, s}
+	}
+	return s.audit
+}
+
+func (s *MemoryStore) Versions() VersionRepository {
+	if s.journal != nil {
+		return journaledVersionRepo{s.versions, s}
+	}
+	return s.versions
+}
+
+// write journals op and then applies it. A write that cannot be journaled is
+// not applied. When the journal has outgrown its limit it is compacted.
+func (s *MemoryStore) write(op string, data any, apply func() error) error {
+	s.writeMu.Lock()
+	if err := s.journal.append(op, data); err != nil {
+		s.writeMu.Unlock()
+		return fmt.Errorf("writing journal: %w", err)
+	}
+	err := apply()
+	compact := s.journal.size >= s.compactAt
+	s.writeMu.Unlock()
+
+	if compact {
+		if cerr := s.Save(); cerr != nil {
+			slog.Error("compacting journal", "error", cerr)
+		}
+	}
+	return err
+}
+
+// Snapshot captures the contents of every repository, sorted so that equal
+// contents give equal snapshots.
+func (s *MemoryStore) Snapshot() *Snapshot {
+	snap := &Snapshot{FormatVersion: SnapshotFormat, TakenAt: time.Now().UTC()}
+
+	s.users.mu.RLock()
+	for _, u := range s.users.user {
+		snap.Users = append(snap.Users, *u)
+	}
+	s.users.mu.RUnlock()
+	slices.SortFunc(snap.Users, func(a, b model.User) int { return cmp.Compare(a.ID, b.ID) })
+
+	s.events.mu.RLock()
+	for _, e := range s.events.data {
+		snap.Events = append(snap.Events, *e)
+	}
+	s.events.mu.RUnlock()
+	slices.SortFunc(snap.Events, func(a, b model.Event) int { return cmp.Compare(a.ID, b.ID) })
+
+	s.availability.mu.RLock()
+	for _, byUser := range s.availability.data {
+		for _, av := range byUser {
+			snap.Availability = append(snap.Availability, av)
+		}
+	}
+	s.availability.mu.RUnlock()
+	slices.SortFunc(snap.Availability, func(a, b model.Availability) int {
+		return cmp.Or(cmp.Compare(a.EventID, b.EventID), cmp.Compare(a.UserID, b.UserID))
+	})
+
+	s.credentials.mu.RLock()
+	for _, c := range s.credentials.byID {
+		snap.Credentials = append(snap.Credentials, StoredCredential{Credential: *c, KeyHash: c.KeyHash})
+	}
+	s.credentials.mu.RUnlock()
+	slices.SortFunc(snap.Credentials, func(a, b StoredCredential) int { return cmp.Compare(a.ID, b.ID) })
+
+	s.links.mu.RLock()
+	for _, l := range s.links.data {
+		snap.MagicLinks = append(snap.MagicLinks, *l)
+	}
+	s.links.mu.RUnlock()
+	slices.SortFunc(snap.MagicLinks, func(a, b model.MagicLink) int { return cmp.Compare(a.ID, b.ID) })
+
+	s.audit.mu.RLock()
+	snap.Audit = slices.Clone(s.audit.entries)
+	s.audit.mu.RUnlock()
+
+	s.versions.mu.RLock()
+	for _, versions := range s.versions.data {
+		snap.Versions = append(snap.Versions, versions...)
+	}
+	s.versions.mu.RUnlock()
+	slices.SortFunc(snap.Versions, func(a, b model.EventVersion) int {
+		return cmp.Or(cmp.Compare(a.EventID, b.EventID), cmp.Compare(a.Version, b.Version))
+	})
+	return snap
+}
+
+// Restore replaces the contents of every repository with those of snap. It
+// bypasses the journal, so a journaled store must be saved afterwards.
+func (s *MemoryStore) Restore(snap *Snapshot) {
+	s.users.mu.Lock()
+	s.users.user = make(map[string]*model.User, len(snap.Users))
+	for _, u := range snap.Users {
+		s.users.user[u.ID] = &u
+	}
+	s.users.mu.Unlock()
+
+	s.events.mu.Lock()
+	s.events.data = make(map[string]*model.Event, len(snap.Events))
+	for _, e := range snap.Events {
+		s.events.data[e.ID] = &e
+	}
+	s.events.mu.Unlock()
+
+	s.availability.mu.Lock()
+	s.availability.data = make(map[string]map[string]model.Availability)
+	for _, av := range snap.Availability {
+		if s.availability.data[av.EventID] == nil {
+			s.availability.data[av.EventID] = make(map[string]model.Availability)
+		}
+		s.availability.data[av.EventID][av.UserID] = av
+	}
+	s.availability.mu.Unlock()
+
+	s.credentials.mu.Lock()
+	s.credentials.byID = make(map[string]*model.Credential, len(snap.Credentials))
+	s.credentials.byHash = make(map[string]*model.Credential, len(snap.Credentials))
+	for _, stored := range snap.Credentials {
+		c := stored.Credential
+		c.KeyHash = stored.KeyHash
+		s.credentials.byID[c.ID] = &c
+		s.credentials.byHash[c.KeyHash] = &c
+	}
+	s.credentials.mu.Unlock()
+
+	s.links.mu.Lock()
+	s.links.data = make(map[string]*model.MagicLink, len(snap.MagicLinks))
+	for _, l := range snap.MagicLinks {
+		s.links.data[l.ID] = &l
+	}
+	s.links.mu.Unlock()
+
+	s.audit.mu.Lock()
+	s.audit.entries = slices.Clone(snap.Audit)
+	s.audit.mu.Unlock()
+
+	s.versions.mu.Lock()
+	s.versions.data = make(map[string][]model.EventVersion)
+	for _, v := range snap.Versions {
+		s.versions.data[v.EventID] = append(s.versions.data[v.EventID], v)
+	}
+	s.versions.mu.Unlock()
+}
+
+// DecodeSnapshot parses a snapshot, migrating it from older format versions.
+func DecodeSnapshot(data []byte) (*Snapshot, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("decoding snapshot: %w", err)
+	}
+	var version int
+	if err := json.Unmarshal(raw["format_version"], &version); err != nil || version < 1 {
+		return nil, errors.New("snapshot has no valid format_version")
+	}
+	if version > SnapshotFormat {
+		return nil, fmt.Errorf("snapshot format %d is newer than the supported format %d", version, SnapshotFormat)
+	}
+	for ; version < SnapshotFormat; version++ {
+		migrate, ok := snapshotMigrations[version]
+		if !ok {
+			return nil, fmt.Errorf("no migration from snapshot format %d", version)
+		}
+		if err := migrate(raw); err != nil {
+			return nil, fmt.Errorf("migrating snapshot from format %d: %w", version, err)
+		}
+	}
+	raw["format_version"], _ = json.Marshal(SnapshotFormat)
+
+	migrated, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(migrated, &snap); err != nil {
+		return nil, fmt.Errorf("decoding snapshot: %w", err)
+	}
+	return &snap, nil
+}
+
+// Save writes a snapshot to the store's path. The snapshot goes to a
+// temporary file in the same directory that is synced and then renamed over
+// the old one, so a crash leaves either the old or the new snapshot intact.
+// Writes wait while a journaled store is saved, and the journal is emptied
+// afterwards. A store without a path is not saved.
+func (s *MemoryStore) Save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	if s.journal == nil {
+		return s.writeSnapshot(s.Snapshot())
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	snap := s.Snapshot()
+	snap.JournalSeq = s.journal.seq
+	if err := s.writeSnapshot(snap); err != nil {
+		return err
+	}
+	s.savedSeq = snap.JournalSeq
+	if err := s.journal.reset(); err != nil {
+		return fmt.Errorf("emptying journal: %w", err)
+	}
+	return nil
+}
+
+func (s *MemoryStore) writeSnapshot(snap *Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("encoding snapshot: %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("syncing snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("replacing snapshot: %w", err)
+	}
+	// Persist the rename itself.
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// RunSnapshots saves the store every interval until ctx is cancelled.
+func (s *MemoryStore) RunSnapshots(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Save(); err != nil {
+				logger.ErrorContext(ctx, "saving snapshot", "error", err)
+			}
+		}
+	}
+}
+
+// Close saves a final snapshot and closes the journal. With a journal, the
+// snapshot is only saved if something was written since the last one, so
+// opening a store to read it leaves its files as they were.
+func (s *MemoryStore) Close(context.Context) error {
+	var err error
+	if s.journal == nil || s.changed() {
+		err = s.Save()
+	}
+	if s.journal != nil {
+		err = errors.Join(err, s.journal.close())
+	}
+	return errors.Join(err, s.unlock())
+}
+
+// changed reports whether the journal holds records the snapshot does not.
+func (s *MemoryStore) changed() bool {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	return s.journal.seq != s.savedSeq
+}
+
+func (s *MemoryStore) unlock() error {
+	if s.lock == nil {
+		return nil
+	}
+	err := s.lock.Close()
+	s.lock = nil
+	return err
+}
@@ -0,0 +1,111 @@
+package repository_test
+
+import (
+	"io/fs"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_SurvivesRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	store, err := repository.OpenMemoryStore(path)
+	require.NoError(t, err)
+	ctx := t.Context()
+
+	start := time.Date(2025, time.May, 20, 9, 0, 0, 0, time.UTC)
+	slots := []model.Slot{{Start: start, End: start.Add(time.Hour)}}
+	require.NoError(t, store.Users().Create(ctx, &model.User{ID: "u1", Name: "Alice"}))
+	require.NoError(t, store.Events().Create(ctx, &model.Event{ID: "e1", Title: "Planning", DurationMin: 60, Slots: slots}))
+	require.NoError(t, store.Events().Create(ctx, &model.Event{ID: "e2", Title: "Old"}))
+	require.NoError(t, store.Events().Trash(ctx, "e2", start))
+	require.NoError(t, store.Availability().Create(ctx, model.Availability{EventID: "e1", UserID: "u1", Slots: slots}))
+	require.NoError(t, store.Credentials().Create(ctx, &model.Credential{ID: "c1", UserID: "u1", KeyHash: "hash", CreatedAt: start}))
+	require.NoError(t, store.MagicLinks().Create(ctx, &model.MagicLink{ID: "l1", EventID: "e1", GuestID: "g1", ExpiresAt: start}))
+	require.NoError(t, store.Audit().Append(ctx, &model.AuditEntry{Actor: "u1", Action: model.AuditCreate, EventID: "e1"}))
+	require.NoError(t, store.Versions().Append(ctx, &model.EventVersion{EventID: "e1", Actor: "u1"}))
+	require.NoError(t, store.Close(ctx))
+
+	leftovers, err := filepath.Glob(path + ".tmp-*")
+	require.NoError(t, err)
+	assert.Empty(t, leftovers, "the temporary file must be renamed away")
+
+	reopened, err := repository.OpenMemoryStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, store.Snapshot().Users, reopened.Snapshot().Users)
+
+	event, err := reopened.Events().Get(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Planning", event.Title)
+	_, err = reopened.Events().Get(ctx, "e2")
+	assert.Error(t, err, "trashed events stay trashed")
+	trashed, err := reopened.Events().GetTrashed(ctx, "e2")
+	require.NoError(t, err)
+	assert.True(t, trashed.DeletedAt.Equal(start))
+
+	av, err := reopened.Availability().Get(ctx, "e1", "u1")
+	require.NoError(t, err)
+	assert.Equal(t, slots, av.Slots)
+	cred, err := reopened.Credentials().GetByKeyHash(ctx, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", cred.UserID)
+	links, err := reopened.MagicLinks().ListByEvent(ctx, "e1")
+	require.NoError(t, err)
+	assert.Len(t, links, 1)
+	history, err := reopened.Audit().Query(ctx, model.AuditFilter{})
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+
+	// New records continue the restored sequences.
+	require.NoError(t, reopened.Versions().Append(ctx, &model.EventVersion{EventID: "e1", Actor: "u1"}))
+	versions, err := reopened.Versions().List(ctx, "e1")
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+}
+
+func TestOpenMemoryStore_MissingFileStartsEmpty(t *testing.T) {
+	store, err := repository.OpenMemoryStore(filepath.Join(t.TempDir(), "none.json"))
+	require.NoError(t, err)
+	users, err := store.Users().GetAll(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestOpenMemoryStore_LocksAgainstOtherOpens(t *testing.T) {
+	dir := t.TempDir()
+	path, journal := filepath.Join(dir, "data.json"), filepath.Join(dir, "data.journal")
+	store, err := repository.OpenMemoryStore(path, repository.WithJournal(journal, 1<<30))
+	require.NoError(t, err)
+
+	_, err = repository.OpenMemoryStore(path, repository.WithJournal(journal, 1<<30))
+	assert.ErrorIs(t, err, repository.ErrStoreLocked)
+
+	require.NoError(t, store.Close(t.Context()))
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, fs.ErrNotExist, "closing without writes saves nothing")
+
+	store, err = repository.OpenMemoryStore(path, repository.WithJournal(journal, 1<<30))
+	require.NoError(t, err, "closing releases the lock")
+	require.NoError(t, store.Users().Create(t.Context(), &model.User{ID: "u1"}))
+	require.NoError(t, store.Close(t.Context()))
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+}
+
+func TestDecodeSnapshot_FormatVersion(t *testing.T) {
+	_, err := repository.DecodeSnapshot([]byte(`{"users": []}`))
+	assert.EqualError(t, err, "snapshot has no valid format_version")
+
+	_, err = repository.DecodeSnapshot([]byte(`{"format_version": 99}`))
+	assert.EqualError(t, err, "snapshot format 99 is newer than the supported format 1")
+
+	snap, err := repository.DecodeSnapshot([]byte(`{"format_version": 1, "users": [{"id": "u1", "name": "Alice"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", snap.Users[0].Name)
+}
@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Tx is a unit of work's view of the repositories it spans. Reads through
+// Users, Events, Availability and Credentials see the transaction's own
+// writes.
+type Tx interface {
+	Users() UserRepository
+	Events() EventRepository
+	Availability() AvailabilityRepository
+	// Credentials are created at once, but deleted only when the
+	// transaction commits.
+	Credentials() CredentialRepository
+	// Audit and Versions take effect when the transaction commits; until
+	// then, reads through them do not see its appends.
+	Audit() AuditRepository
+	Versions() VersionRepository
+}
+
+// UnitOfWork groups writes to several repositories so they take effect
+// together or not at all. A SQL backend implements Do with BEGIN, COMMIT and
+// ROLLBACK and hands fn repositories bound to the transaction.
+type UnitOfWork interface {
+	// Do runs fn in a new transaction. If fn returns an error or panics,
+	// every write it made through tx is rolled back and the error is
+	// returned or the panic resumed. Do must not be called from inside fn.
+	Do(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error
+}
+
+type inMemoryUnitOfWork struct {
+	users        UserRepository
+	events       EventRepository
+	availability AvailabilityRepository
+	credentials  CredentialRepository
+	audit        AuditRepository
+	versions     VersionRepository
+	mu           sync.Mutex
+}
+
+// NewInMemoryUnitOfWork returns a UnitOfWork over repositories that have no
+// transactions of their own. User, event and availability writes and
+// credential creations are applied immediately and undone on rollback by
+// writing back what they replaced, in reverse order. Credential deletions,
+// audit entries and version changes cannot be undone, so they are held back
+// until commit, which applies version changes first, then credential
+// deletions and audit entries last. If one fails, the rest are dropped and
+// the transaction is rolled back; those already applied are kept.
+// Transactions run one at a time, but writes made outside a transaction may
+// interleave with them. The undoing writes go through the same repositories,
+// so a journal records them like any other write.
+func NewInMemoryUnitOfWork(users UserRepository, events EventRepository, availability AvailabilityRepository, credentials CredentialRepository, audit AuditRepository, versions VersionRepository) UnitOfWork {
+	return &inMemoryUnitOfWork{
+		users:        users,
+		events:       events,
+		availability: availability,
+		credentials:  credentials,
+		audit:        audit,
+		versions:     versions,
+	}
+}
+
+func (u *inMemoryUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, tx Tx) error) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	tx := &undoTx{
+		users:        u.users,
+		events:       u.events,
+		availability: u.availability,
+		credentials:  u.credentials,
+		audit:        u.audit,
+		versions:     u.versions,
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.rollback(context.WithoutCancel(ctx))
+			panic(p)
+		}
+	}()
+	err := fn(ctx, tx)
+	if err == nil {
+		err = tx.commit(ctx)
+	}
+	if err != nil {
+		// The writes being undone were made under ctx; rolling back must
+		// not stop halfway because the caller has gone away.
+		if rerr := tx.rollback(context.WithoutCancel(ctx)); rerr != nil {
+			return errors.Join(err, fmt.Errorf("rolling back: %w", rerr))
+		}
+		return err
+	}
+	return nil
+}
+
+// undoTx records how to undo each write made through it, and the appends
+// it holds back until commit.
+type undoTx struct {
+	users         UserRepository
+	events        EventRepository
+	availability  AvailabilityRepository
+	credentials   CredentialRepository
+	audit         AuditRepository
+	versions      VersionRepository
+	undo          []func(ctx context.Context) error
+	versionLog    []func(ctx context.Context) error
+	credentialLog []func(ctx context.Context) error
+	auditLog      []func(ctx context.Context) error
+}
+
+func (tx *undoTx) Users() UserRepository   { return txUserRepo{tx.users, tx} }
+func (tx *undoTx) Events() EventRepository { return txEventRepo{tx.events, tx} }
+func (tx *undoTx) Availability() AvailabilityRepository {
+	return txAvailabilityRepo{tx.availability, tx}
+}
+func (tx *undoTx) Credentials() CredentialRepository {
+	return txCredentialRepo{tx.credentials, tx}
+}
+func (tx *undoTx) Audit() AuditRepository      { return txAuditRepo{tx.audit, tx} }
+func (tx *undoTx) Versions() VersionRepository { return txVersionRepo{tx.versions, tx} }
+
+// commit applies the held-back writes, each kind in the order they were
+// made, and stops at the first failure.
+func (tx *undoTx) commit(ctx context.Context) error {
+	for _, apply := range slices.Concat(tx.versionLog, tx.credentialLog, tx.auditLog) {
+		if err := apply(ctx); err != nil {
+			return err
+		}
+	}
+	tx.versionLog, tx.credentialLog, tx.auditLog = nil, nil, nil
+	return nil
+}
+
+// rollback runs the undo steps newest first. It carries on past failures so
+// as much as possible is undone, and reports them all.
+func (tx *undoTx) rollback(ctx context.Context) error {
+	var errs []error
+	for _, undo := range slices.Backward(tx.undo) {
+		if err := undo(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	tx.undo = nil
+	return errors.Join(errs...)
+}
+
+type txUserRepo struct {
+	UserRepository
+	tx *undoTx
+}
+
+func (r txUserRepo) Create(ctx context.Context, user *model.User) error {
+	prev, err := r.UserRepository.Get(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepository.Create(ctx, user); err != nil {
+		return err
+	}
+	r.tx.undo = append(r.tx.undo, func(ctx context.Context) error {
+		if prev == nil {
+			return r.UserRepository.Delete(ctx, user.ID)
+		}
+		return r.UserRepository.Create(ctx, prev)
+	})
+	return nil
+}
+
+func (r txUserRepo) Delete(ctx context.Context, id string) error {
+	prev, err := r.UserRepository.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.tx.undo = append(r.tx.undo, func(ctx context.Context) error {
+		return r.UserRepository.Create(ctx, prev)
+	})
+	return nil
+}
+
+type txEventRepo struct {
+	EventRepository
+	tx *undoTx
+}
+
+// write applies a write to the event id, first remembering the event as it
+// was, trashed or not, so that undoing puts it back or removes it.
+func (r txEventRepo) write(ctx context.Context, id string, apply func() error) error {
+	prev, err := r.EventRepository.Get(ctx, id)
+	if err != nil {
+		prev, _ = r.EventRepository.GetTrashed(ctx, id)
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+	r.tx.undo = append(r.tx.undo, func(ctx context.Context) error {
+		if prev == nil {
+			return r.EventRepository.Delete(ctx, id)
+		}
+		return r.EventRepository.Create(ctx, prev)
+	})
+	return nil
+}
+
+func (r txEventRepo) Create(ctx context.Context, event *model.Event) error {
+	return r.write(ctx, event.ID, func() error { return r.EventRepository.Create(ctx, event) })
+}
+
+func (r txEventRepo) Update(ctx context.Context, event *model.Event) error {
+	return r.write(ctx, event.ID, func() error { return r.EventRepository.Update(ctx, event) })
+}
+
+func (r txEventRepo) Delete(ctx context.Context, id string) error {
+	return r.write(ctx, id, func() error { return r.EventRepository.Delete(ctx, id) })
+}
+
+func (r txEventRepo) Trash(ctx context.Context, id string, at time.Time) error {
+	return r.write(ctx, id, func() error { return r.EventRepository.Trash(ctx, id, at) })
+}
+
+func (r txEventRepo) Restore(ctx context.Context, id string) error {
+	return r.write(ctx, id, func() error { return r.EventRepository.Restore(ctx, id) })
+}
+
+type txAvailabilityRepo struct {
+	AvailabilityRepository
+	tx *undoTx
+}
+
+func (r txAvailabilityRepo) Create(ctx context.Context, av model.Availability) error {
+	if err := r.AvailabilityRepository.Create(ctx, av); err != nil {
+		return err
+	}
+	r.tx.undo = append(r.tx.undo, func(ctx context.Context) error {
+		return r.AvailabilityRepository.Delete(ctx, av.EventID, av.UserID)
+	})
+	return nil
+}
+
+func (r txAvailabilityRepo) Update(ctx context.Context, av model.Availability) error {
+	prev, err := r.AvailabilityRepository.Get(ctx, av.EventID, av.UserID)
+	if err != nil {
+		return err
+	}
+	if err := r.AvailabilityRepository.Update(ctx, av); err != nil {
+		return err
+	}
+	r.tx.undo = append(r.tx.undo, func(ctx context.Context) error {
+		return r.AvailabilityRepository.Update(ctx, prev)
+	})
+	return nil
+}
+
+func (r txAvailabilityRepo) Delete(ctx context.Context, eventID, userID string) error {
+	prev, err := r.AvailabilityRepository.Get(ctx, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if err := r.AvailabilityRepository.Delete(ctx, eventID, userID); err != nil {
+		return err
+	}
+	r.tx.undo = append(r.tx.undo, func(ctx context.Context) error {
+		return r.AvailabilityRepository.Create(ctx, prev)
+	})
+	return nil
+}
+
+func (r txAvailabilityRepo) DeleteByEvent(ctx context.Context, eventID string) error {
+	prev, err := r.AvailabilityRepository.GetByEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := r.AvailabilityRepository.DeleteByEvent(ctx, eventID); err != nil {
+		return err
+	}
+	r.tx.undo = append(r.tx.undo, func(ctx context.Context) error {
+		var errs []error
+		for _, av := range prev {
+			errs = append(errs, r.AvailabilityRepository.Create(ctx, av))
+		}
+		return errors.Join(errs...)
+	})
+	return nil
+}
+
+type txCredentialRepo struct {
+	CredentialRepository
+	tx *undoTx
+}
+
+func (r txCredentialRepo) Create(ctx context.Context, cred *model.Credential) error {
+	if err := r.CredentialRepository.Create(ctx, cred); err != nil {
+		return err
+	}
+	r.tx.undo = append(r.tx.undo, func(ctx context.Context) error {
+		return r.CredentialRepository.Delete(ctx, cred.ID)
+	})
+	return nil
+}
+
+func (r txCredentialRepo) Delete(_ context.Context, id string) error {
+	r.tx.credentialLog = append(r.tx.credentialLog, func(ctx context.Context) error {
+		return r.CredentialRepository.Delete(ctx, id)
+	})
+	return nil
+}
+
+type txAuditRepo struct {
+	AuditRepository
+	tx *undoTx
+}
+
+func (r txAuditRepo) Append(_ context.Context, entry *model.AuditEntry) error {
+	r.tx.auditLog = append(r.tx.auditLog, func(ctx context.Context) error {
+		return r.AuditRepository.Append(ctx, entry)
+	})
+	return nil
+}
+
+type txVersionRepo struct {
+	VersionRepository
+	tx *undoTx
+}
+
+func (r txVersionRepo) Append(_ context.Context, v *model.EventVersion) error {
+	r.tx.versionLog = append(r.tx.versionLog, func(ctx context.Context) error {
+		return r.VersionRepository.Append(ctx, v)
+	})
+	return nil
+}
+
+func (r txVersionRepo) DeleteByEvent(_ context.Context, eventID string) error {
+	r.tx.versionLog = append(r.tx.versionLog, func(ctx context.Context) error {
+		return r.VersionRepository.DeleteByEvent(ctx, eventID)
+	})
+	return nil
+}
+
+// PurgeEvent deletes the event id for good, along with its availability and
+// versions, through the repositories of one transaction. Magic links cannot
+// be rolled back, so callers delete them once the transaction has committed.
+func PurgeEvent(ctx context.Context, events EventRepository, availability AvailabilityRepository, versions VersionRepository, id string) error {
+	if err := availability.DeleteByEvent(ctx, id); err != nil {
+		return err
+	}
+	if err := events.Delete(ctx, id); err != nil {
+		return err
+	}
+	return versions.DeleteByEvent(ctx, id)
+}
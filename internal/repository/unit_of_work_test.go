@@ -0,0 +1,204 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seededUnitOfWork returns a unit of work over repositories holding a live
+// event e1 with availability from u1 and u2, and a trashed event e2.
+func seededUnitOfWork(t *testing.T) (repository.UnitOfWork, repository.EventRepository, repository.AvailabilityRepository) {
+	t.Helper()
+	ctx := t.Context()
+	events, availability := repository.NewInMemoryEventRepository(), repository.NewInMemoryAvailabilityRepository()
+	require.NoError(t, events.Create(ctx, &model.Event{ID: "e1", Title: "Planning"}))
+	require.NoError(t, events.Create(ctx, &model.Event{ID: "e2", Title: "Old"}))
+	require.NoError(t, events.Trash(ctx, "e2", time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, availability.Create(ctx, model.Availability{EventID: "e1", UserID: "u1"}))
+	require.NoError(t, availability.Create(ctx, model.Availability{EventID: "e1", UserID: "u2"}))
+	uow := repository.NewInMemoryUnitOfWork(repository.NewInMemoryUserRepository(), events, availability,
+		repository.NewInMemoryCredentialRepository(), repository.NewInMemoryAuditRepository(), repository.NewInMemoryVersionRepository())
+	return uow, events, availability
+}
+
+func TestUnitOfWork_CommitsOnSuccess(t *testing.T) {
+	uow, events, availability := seededUnitOfWork(t)
+	err := uow.Do(t.Context(), func(ctx context.Context, tx repository.Tx) error {
+		if err := tx.Events().Update(ctx, &model.Event{ID: "e1", Title: "Final"}); err != nil {
+			return err
+		}
+		event, err := tx.Events().Get(ctx, "e1")
+		require.NoError(t, err)
+		assert.Equal(t, "Final", event.Title, "the transaction reads its own writes")
+		return tx.Availability().Delete(ctx, "e1", "u1")
+	})
+	require.NoError(t, err)
+
+	event, err := events.Get(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Final", event.Title)
+	entries, err := availability.GetByEvent(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestUnitOfWork_RollsBackOnError(t *testing.T) {
+	uow, events, availability := seededUnitOfWork(t)
+	ctx := t.Context()
+	before, err := availability.GetByEvent(ctx, "e1")
+	require.NoError(t, err)
+	boom := errors.New("boom")
+
+	err = uow.Do(ctx, func(ctx context.Context, tx repository.Tx) error {
+		require.NoError(t, tx.Events().Create(ctx, &model.Event{ID: "e3", Title: "New"}))
+		require.NoError(t, tx.Events().Update(ctx, &model.Event{ID: "e1", Title: "Final"}))
+		require.NoError(t, tx.Events().Trash(ctx, "e1", time.Now()))
+		require.NoError(t, tx.Events().Restore(ctx, "e2"))
+		require.NoError(t, tx.Availability().Update(ctx, model.Availability{EventID: "e1", UserID: "u1", Declined: true}))
+		require.NoError(t, tx.Availability().Create(ctx, model.Availability{EventID: "e1", UserID: "u3"}))
+		require.NoError(t, tx.Availability().Delete(ctx, "e1", "u2"))
+		require.NoError(t, tx.Availability().DeleteByEvent(ctx, "e1"))
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+
+	event, err := events.Get(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Planning", event.Title)
+	_, err = events.Get(ctx, "e3")
+	assert.Error(t, err, "created events are removed")
+	_, err = events.GetTrashed(ctx, "e2")
+	assert.NoError(t, err, "restored events go back to the trash")
+	after, err := availability.GetByEvent(ctx, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+}
+
+func TestUnitOfWork_RollsBackOnPanic(t *testing.T) {
+	uow, events, _ := seededUnitOfWork(t)
+	assert.PanicsWithValue(t, "boom", func() {
+		uow.Do(t.Context(), func(ctx context.Context, tx repository.Tx) error {
+			require.NoError(t, tx.Events().Delete(ctx, "e1"))
+			panic("boom")
+		})
+	})
+	_, err := events.Get(t.Context(), "e1")
+	assert.NoError(t, err)
+}
+
+func TestUnitOfWork_FailedWritesAreNotUndone(t *testing.T) {
+	uow, events, _ := seededUnitOfWork(t)
+	err := uow.Do(t.Context(), func(ctx context.Context, tx repository.Tx) error {
+		// e2 is trashed, so the update fails and leaves nothing to undo.
+		return tx.Events().Update(ctx, &model.Event{ID: "e2", Title: "Revived"})
+	})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "rolling back")
+	trashed, err := events.GetTrashed(t.Context(), "e2")
+	require.NoError(t, err)
+	assert.Equal(t, "Old", trashed.Title)
+}
+
+func TestUnitOfWork_AppendsOnCommitOnly(t *testing.T) {
+	ctx := t.Context()
+	events, audit, versions := repository.NewInMemoryEventRepository(), repository.NewInMemoryAuditRepository(), repository.NewInMemoryVersionRepository()
+	uow := repository.NewInMemoryUnitOfWork(repository.NewInMemoryUserRepository(), events, repository.NewInMemoryAvailabilityRepository(),
+		repository.NewInMemoryCredentialRepository(), audit, versions)
+	write := func(ctx context.Context, tx repository.Tx) error {
+		if err := tx.Audit().Append(ctx, &model.AuditEntry{Actor: "u1", EventID: "e1"}); err != nil {
+			return err
+		}
+		if err := tx.Versions().Append(ctx, &model.EventVersion{EventID: "e1", Actor: "u1"}); err != nil {
+			return err
+		}
+		entries, err := audit.Query(ctx, model.AuditFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, entries, "appends wait for the commit")
+		return tx.Events().Create(ctx, &model.Event{ID: "e1"})
+	}
+
+	boom := errors.New("boom")
+	err := uow.Do(ctx, func(ctx context.Context, tx repository.Tx) error {
+		require.NoError(t, write(ctx, tx))
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+	entries, err := audit.Query(ctx, model.AuditFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a rolled back transaction appends nothing")
+	history, err := versions.List(ctx, "e1")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	require.NoError(t, uow.Do(ctx, write))
+	entries, err = audit.Query(ctx, model.AuditFilter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	history, err = versions.List(ctx, "e1")
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+// failingVersions rejects every append.
+type failingVersions struct{ repository.VersionRepository }
+
+func (failingVersions) Append(context.Context, *model.EventVersion) error {
+	return errors.New("disk full")
+}
+
+func TestUnitOfWork_RollsBackWhenCommitFails(t *testing.T) {
+	ctx := t.Context()
+	events := repository.NewInMemoryEventRepository()
+	uow := repository.NewInMemoryUnitOfWork(repository.NewInMemoryUserRepository(), events, repository.NewInMemoryAvailabilityRepository(),
+		repository.NewInMemoryCredentialRepository(), repository.NewInMemoryAuditRepository(), failingVersions{repository.NewInMemoryVersionRepository()})
+	err := uow.Do(ctx, func(ctx context.Context, tx repository.Tx) error {
+		if err := tx.Events().Create(ctx, &model.Event{ID: "e1"}); err != nil {
+			return err
+		}
+		return tx.Versions().Append(ctx, &model.EventVersion{EventID: "e1"})
+	})
+	assert.EqualError(t, err, "disk full")
+	_, err = events.Get(ctx, "e1")
+	assert.Error(t, err, "the event is removed again")
+}
+
+func TestUnitOfWork_RollsBackUsersAndCredentials(t *testing.T) {
+	ctx := t.Context()
+	users, credentials := repository.NewInMemoryUserRepository(), repository.NewInMemoryCredentialRepository()
+	require.NoError(t, users.Create(ctx, &model.User{ID: "u1", Name: "Alice"}))
+	require.NoError(t, credentials.Create(ctx, &model.Credential{ID: "c1", UserID: "u1", KeyHash: "h1"}))
+	uow := repository.NewInMemoryUnitOfWork(users, repository.NewInMemoryEventRepository(), repository.NewInMemoryAvailabilityRepository(),
+		credentials, repository.NewInMemoryAuditRepository(), repository.NewInMemoryVersionRepository())
+
+	boom := errors.New("boom")
+	err := uow.Do(ctx, func(ctx context.Context, tx repository.Tx) error {
+		require.NoError(t, tx.Users().Create(ctx, &model.User{ID: "u1", Name: "Renamed"}))
+		require.NoError(t, tx.Users().Create(ctx, &model.User{ID: "u2", Name: "Bob"}))
+		require.NoError(t, tx.Credentials().Create(ctx, &model.Credential{ID: "c2", UserID: "u2", KeyHash: "h2"}))
+		require.NoError(t, tx.Credentials().Delete(ctx, "c1"))
+		_, err := tx.Credentials().GetByKeyHash(ctx, "h1")
+		assert.NoError(t, err, "deletions wait for the commit")
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+	all, err := users.GetAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*model.User{"u1": {ID: "u1", Name: "Alice"}}, all)
+	_, err = credentials.GetByKeyHash(ctx, "h2")
+	assert.Error(t, err, "the created credential is removed again")
+	_, err = credentials.GetByKeyHash(ctx, "h1")
+	assert.NoError(t, err)
+
+	require.NoError(t, uow.Do(ctx, func(ctx context.Context, tx repository.Tx) error {
+		return tx.Credentials().Delete(ctx, "c1")
+	}))
+	_, err = credentials.GetByKeyHash(ctx, "h1")
+	assert.Error(t, err)
+}
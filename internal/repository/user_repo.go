@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"fmt"
 	"maps"
 	"meeting-scheduler/internal/model"
 	"sync"
@@ -15,21 +17,36 @@ func NewInMemoryUserRepository() UserRepository {
 	return &inMemoryUserRepo{user: make(map[string]*model.User)}
 }
 
-func (r *inMemoryUserRepo) Get(id string) (*model.User, error) {
+func (r *inMemoryUserRepo) Get(_ context.Context, id string) (*model.User, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	return r.user[id], nil
 }
-func (r *inMemoryUserRepo) GetAll() (map[string]*model.User, error) {
+func (r *inMemoryUserRepo) GetAll(_ context.Context) (map[string]*model.User, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	copy := make(map[string]*model.User, len(r.user))
 	maps.Copy(copy, r.user)
 	return copy, nil
 }
-func (r *inMemoryUserRepo) Create(e *model.User) error {
+func (r *inMemoryUserRepo) Create(_ context.Context, e *model.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.user[e.ID] = e
 	return nil
 }
+func (r *inMemoryUserRepo) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.user[id]; !ok {
+		return fmt.Errorf("user not found: %s", id)
+	}
+	delete(r.user, id)
+	return nil
+}
+
+func (r *inMemoryUserRepo) Ping(_ context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return nil
+}
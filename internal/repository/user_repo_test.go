@@ -12,10 +12,10 @@ func TestInMemoryUserRepo_CreateGet(t *testing.T) {
 	repo := repository.NewInMemoryUserRepository()
 
 	user := &model.User{ID: "u1", Name: "Alice"}
-	err := repo.Create(user)
+	err := repo.Create(t.Context(), user)
 	assert.NoError(t, err)
 
-	gotUser, err := repo.Get("u1")
+	gotUser, err := repo.Get(t.Context(), "u1")
 	assert.NoError(t, err)
 	assert.Equal(t, user, gotUser)
 }
@@ -26,12 +26,23 @@ func TestInMemoryUserRepo_GetAll(t *testing.T) {
 	user1 := &model.User{ID: "u1", Name: "Alice"}
 	user2 := &model.User{ID: "u2", Name: "Bob"}
 
-	_ = repo.Create(user1)
-	_ = repo.Create(user2)
+	_ = repo.Create(t.Context(), user1)
+	_ = repo.Create(t.Context(), user2)
 
-	allUsers, err := repo.GetAll()
+	allUsers, err := repo.GetAll(t.Context())
 	assert.NoError(t, err)
 	assert.Len(t, allUsers, 2)
 	assert.Contains(t, allUsers, "u1")
 	assert.Contains(t, allUsers, "u2")
 }
+
+func TestInMemoryUserRepo_Delete(t *testing.T) {
+	repo := repository.NewInMemoryUserRepository()
+	_ = repo.Create(t.Context(), &model.User{ID: "u1", Name: "Alice"})
+
+	assert.NoError(t, repo.Delete(t.Context(), "u1"))
+	gotUser, err := repo.Get(t.Context(), "u1")
+	assert.NoError(t, err)
+	assert.Nil(t, gotUser)
+	assert.EqualError(t, repo.Delete(t.Context(), "u1"), "user not found: u1")
+}
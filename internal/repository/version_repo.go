@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"sync"
+)
+
+// [event -> versions, oldest first]
+type inMemoryVersionRepo struct {
+	data map[string][]model.EventVersion
+	mu   sync.RWMutex
+}
+
+func NewInMemoryVersionRepository() VersionRepository {
+	return &inMemoryVersionRepo{data: make(map[string][]model.EventVersion)}
+}
+
+// Append numbers the version after the event's latest one and stores a copy.
+func (r *inMemoryVersionRepo) Append(_ context.Context, v *model.EventVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	v.Version = len(r.data[v.EventID]) + 1
+	r.data[v.EventID] = append(r.data[v.EventID], *v)
+	return nil
+}
+func (r *inMemoryVersionRepo) Get(_ context.Context, eventID string, version int) (*model.EventVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	versions := r.data[eventID]
+	if version < 1 || version > len(versions) {
+		return nil, fmt.Errorf("version %d not found for event %s", version, eventID)
+	}
+	v := versions[version-1]
+	return &v, nil
+}
+func (r *inMemoryVersionRepo) List(_ context.Context, eventID string) ([]model.EventVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]model.EventVersion{}, r.data[eventID]...), nil
+}
+func (r *inMemoryVersionRepo) DeleteByEvent(_ context.Context, eventID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.data, eventID)
+	return nil
+}
+
+func (r *inMemoryVersionRepo) Ping(_ context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return nil
+}
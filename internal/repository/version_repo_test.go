@@ -0,0 +1,40 @@
+package repository_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryVersionRepo_AppendGetList(t *testing.T) {
+	repo := repository.NewInMemoryVersionRepository()
+
+	v1 := &model.EventVersion{EventID: "e1", Event: &model.Event{ID: "e1", Title: "First"}}
+	v2 := &model.EventVersion{EventID: "e1", Event: &model.Event{ID: "e1", Title: "Second"}}
+	other := &model.EventVersion{EventID: "e2"}
+	require.NoError(t, repo.Append(t.Context(), v1))
+	require.NoError(t, repo.Append(t.Context(), v2))
+	require.NoError(t, repo.Append(t.Context(), other))
+	assert.Equal(t, 1, v1.Version)
+	assert.Equal(t, 2, v2.Version)
+	assert.Equal(t, 1, other.Version)
+
+	got, err := repo.Get(t.Context(), "e1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Second", got.Event.Title)
+
+	_, err = repo.Get(t.Context(), "e1", 3)
+	assert.Error(t, err)
+	_, err = repo.Get(t.Context(), "e1", 0)
+	assert.Error(t, err)
+
+	events, err := repo.List(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+	events, err = repo.List(t.Context(), "missing")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"meeting-scheduler/internal/archive"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"slices"
+)
+
+// ExportArchive returns every user, event, availability entry and credential
+// as an archive. Only admins may export.
+func (s *SchedulerService) ExportArchive(ctx context.Context, actorID string) (*model.Archive, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.ExportArchive")
+	defer span.End()
+	if !s.IsAdmin(actorID) {
+		return nil, fmt.Errorf("%w: exporting data is restricted to admins", ErrForbidden)
+	}
+	return archive.Export(ctx, s.archiveRepositories(), s.now())
+}
+
+// ImportArchive merges a into the stored data or replaces the stored data
+// with it, as mode says. A dry run only reports what would change. Only
+// admins may import, and each import that writes is recorded in the audit
+// log with its report.
+func (s *SchedulerService) ImportArchive(ctx context.Context, actorID string, a *model.Archive, mode string, dryRun bool) (*model.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.ImportArchive")
+	defer span.End()
+	if !s.IsAdmin(actorID) {
+		return nil, fmt.Errorf("%w: importing data is restricted to admins", ErrForbidden)
+	}
+	return s.importArchive(ctx, actorID, a, mode, dryRun)
+}
+
+// RestoreArchive imports a as ImportArchive does, but for callers that work
+// on the stores directly, such as schedctl on a data file, and so need not
+// be admins. The import is recorded in the audit log as made by actorID.
+func (s *SchedulerService) RestoreArchive(ctx context.Context, actorID string, a *model.Archive, mode string, dryRun bool) (*model.ImportReport, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.RestoreArchive")
+	defer span.End()
+	return s.importArchive(ctx, actorID, a, mode, dryRun)
+}
+
+func (s *SchedulerService) importArchive(ctx context.Context, actorID string, a *model.Archive, mode string, dryRun bool) (*model.ImportReport, error) {
+	report, err := archive.Import(ctx, s.archiveRepositories(), ArchiveValidator{}, a, mode, dryRun)
+	if err != nil || dryRun {
+		return report, err
+	}
+	if err := s.appendAudit(ctx, actorID, model.AuditImport, model.EntityArchive, "", "", nil, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *SchedulerService) archiveRepositories() archive.Repositories {
+	return archive.Repositories{
+		Users:        s.userRepo,
+		Events:       s.eventRepo,
+		Availability: s.availabilityRepo,
+		Credentials:  s.credentialRepo,
+		MagicLinks:   s.linkRepo,
+		UnitOfWork:   s.uow,
+	}
+}
+
+// ArchiveValidator checks archived events and availability with the rules
+// CreateEvent and SetAvailability apply. Archived events also need an
+// organizer who exists, slots that end after they start and, once
+// finalized, sessions FinalizeEvent would accept.
+type ArchiveValidator struct{}
+
+func (ArchiveValidator) ValidateEvent(e *model.Event, users map[string]bool) error {
+	if len(e.Participants) == 0 {
+		return fmt.Errorf("event must have at least one participant")
+	}
+	if e.Organizer == "" {
+		return fmt.Errorf("event has no organizer")
+	}
+	ids := append([]string{e.Organizer}, e.Participants...)
+	if err := ensureKnown(users, append(ids, e.CoOrganizers...)...); err != nil {
+		return err
+	}
+	if err := validateSlots(e.Slots); err != nil {
+		return err
+	}
+	if err := validateQuorum(e); err != nil {
+		return err
+	}
+	if err := validateSplit(e); err != nil {
+		return err
+	}
+	if len(e.FinalSessions) > 0 {
+		return validateSessions(e, e.FinalSessions)
+	}
+	return nil
+}
+
+// ValidateAvailability accepts availability from the event's guests as well
+// as from users.
+func (ArchiveValidator) ValidateAvailability(av model.Availability, e *model.Event, users map[string]bool) error {
+	isGuest := slices.ContainsFunc(e.Guests, func(g model.Guest) bool { return g.ID == av.UserID })
+	if err := ensureKnown(users, av.UserID); err != nil && !isGuest {
+		return err
+	}
+	if err := validateSlots(av.Slots); err != nil {
+		return err
+	}
+	return validateDeclined(av)
+}
+
+// ensureKnown is ensureUsersExist for a set of users at hand.
+func ensureKnown(users map[string]bool, userIDs ...string) error {
+	var missing []string
+	for _, id := range userIDs {
+		if !users[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing users: %v", missing)
+	}
+	return nil
+}
+
+func validateSlots(slots []model.Slot) error {
+	for i, slot := range slots {
+		if !slot.Start.Before(slot.End) {
+			return fmt.Errorf("slot %d does not end after it starts", i+1)
+		}
+	}
+	return nil
+}
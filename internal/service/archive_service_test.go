@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"encoding/json"
+	"meeting-scheduler/internal/archive"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive_AdminOnlyAndAudited(t *testing.T) {
+	svc := newInMemoryService(t, 2, service.WithAdmins("u1"))
+	require.NoError(t, svc.CreateEvent(t.Context(), "u2", &model.Event{
+		ID: "e1", Title: "Planning", DurationMin: 60, Participants: participants(2),
+	}))
+
+	_, err := svc.ExportArchive(t.Context(), "u2")
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	a, err := svc.ExportArchive(t.Context(), "u1")
+	require.NoError(t, err)
+	_, err = svc.ImportArchive(t.Context(), "u2", a, model.ImportMerge, false)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	report, err := svc.ImportArchive(t.Context(), "u1", a, model.ImportReplace, true)
+	require.NoError(t, err)
+	assert.Equal(t, model.ImportCounts{Unchanged: 1}, report.Events)
+	report, err = svc.ImportArchive(t.Context(), "u1", a, model.ImportReplace, false)
+	require.NoError(t, err)
+
+	entries, err := svc.QueryAudit(t.Context(), "u1", model.AuditFilter{Entity: model.EntityArchive})
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "only the import that wrote is recorded")
+	assert.Equal(t, model.AuditImport, entries[0].Action)
+	var recorded model.ImportReport
+	require.NoError(t, json.Unmarshal(entries[0].After, &recorded))
+	assert.Equal(t, *report, recorded)
+}
+
+func TestArchive_KeysSurviveImport(t *testing.T) {
+	svc := newInMemoryService(t, 0, service.WithAdmins("u1"))
+	registration, err := svc.RegisterUser(t.Context(), &model.User{ID: "u1", Name: "Alice"})
+	require.NoError(t, err)
+	a, err := svc.ExportArchive(t.Context(), "u1")
+	require.NoError(t, err)
+
+	other := newInMemoryService(t, 1, service.WithAdmins("u1"))
+	report, err := other.ImportArchive(t.Context(), "u1", a, model.ImportMerge, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.ImportCounts{Created: 1}, report.Credentials)
+	user, err := other.Authenticate(t.Context(), registration.Credential.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", user.ID)
+}
+
+func TestArchive_RejectsEventsTheServiceWouldNot(t *testing.T) {
+	svc := newInMemoryService(t, 2, service.WithAdmins("u1"))
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", Title: "Planning", DurationMin: 60, Participants: participants(2),
+	}))
+	a, err := svc.ExportArchive(t.Context(), "u1")
+	require.NoError(t, err)
+	a.Events[0].Split = &model.SplitConfig{MaxSessions: 2, MinSessionMin: 90}
+
+	_, err = svc.ImportArchive(t.Context(), "u1", a, model.ImportReplace, false)
+	assert.ErrorIs(t, err, archive.ErrInvalid)
+	assert.ErrorContains(t, err, "split min_session_min must be between 1 and 60")
+}
+
+func TestArchive_RoundTripsGuestAvailability(t *testing.T) {
+	svc := newInMemoryService(t, 1, service.WithAdmins("u1"))
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", Title: "Planning", DurationMin: 60, Participants: participants(1),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+	link, err := svc.CreateMagicLink(t.Context(), "u1", "e1", model.MagicLinkRequest{Name: "Visitor"})
+	require.NoError(t, err)
+	guest, err := svc.AuthenticateGuest(t.Context(), link.Token)
+	require.NoError(t, err)
+	_, err = svc.SubmitGuestAvailability(t.Context(), guest, []model.Slot{{Start: at(9, 0), End: at(10, 0)}})
+	require.NoError(t, err)
+	a, err := svc.ExportArchive(t.Context(), "u1")
+	require.NoError(t, err)
+
+	other := newInMemoryService(t, 1, service.WithAdmins("u1"))
+	report, err := other.ImportArchive(t.Context(), "u1", a, model.ImportMerge, false)
+	require.NoError(t, err)
+	assert.Equal(t, model.ImportCounts{Created: 1}, report.Availability)
+	restored, err := other.ExportArchive(t.Context(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, a.Availability, restored.Availability)
+}
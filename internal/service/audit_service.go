@@ -0,0 +1,146 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"sort"
+)
+
+// IsAdmin reports whether the user was granted administrative access.
+func (s *SchedulerService) IsAdmin(userID string) bool {
+	_, ok := s.admins[userID]
+	return ok
+}
+
+// GetEventHistory returns every recorded change to the event and its
+// availability, oldest first.
+func (s *SchedulerService) GetEventHistory(ctx context.Context, eventID string) ([]model.AuditEntry, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.GetEventHistory", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	if eventID == "" {
+		return nil, fmt.Errorf("event ID cannot be empty")
+	}
+	return s.auditRepo.Query(ctx, model.AuditFilter{EventID: eventID})
+}
+
+// QueryAudit searches the whole audit log. Only admins may do so.
+func (s *SchedulerService) QueryAudit(ctx context.Context, actorID string, filter model.AuditFilter) ([]model.AuditEntry, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.QueryAudit")
+	defer span.End()
+	if !s.IsAdmin(actorID) {
+		return nil, fmt.Errorf("%w: the audit log is restricted to admins", ErrForbidden)
+	}
+	return s.auditRepo.Query(ctx, filter)
+}
+
+// record appends an audit entry for a mutation and, when the mutation
+// touches an event, a new version of that event. before is nil for creates
+// and after is nil for deletes.
+func (s *SchedulerService) record(ctx context.Context, actorID, action, entity, entityID, eventID string, before, after any) error {
+	if err := s.appendAudit(ctx, actorID, action, entity, entityID, eventID, before, after); err != nil {
+		return err
+	}
+	if eventID == "" {
+		return nil
+	}
+	return s.snapshotEvent(ctx, actorID, eventID, action+" "+entity)
+}
+
+func (s *SchedulerService) appendAudit(ctx context.Context, actorID, action, entity, entityID, eventID string, before, after any) error {
+	entry := model.AuditEntry{
+		Timestamp: s.now(),
+		Actor:     actorID,
+		Action:    action,
+		Entity:    entity,
+		EntityID:  entityID,
+		EventID:   eventID,
+	}
+	var err error
+	if entry.Before, err = marshalState(before); err != nil {
+		return err
+	}
+	if entry.After, err = marshalState(after); err != nil {
+		return err
+	}
+	if entry.Changes, err = diffFields(entry.Before, entry.After); err != nil {
+		return err
+	}
+	if err := s.auditRepo.Append(ctx, &entry); err != nil {
+		return fmt.Errorf("recording audit entry: %w", err)
+	}
+	return nil
+}
+
+func (s *SchedulerService) recordAvailability(ctx context.Context, actorID, action string, before, after *model.Availability) error {
+	ref := after
+	if ref == nil {
+		ref = before
+	}
+	var b, a any
+	if before != nil {
+		b = before
+	}
+	if after != nil {
+		a = after
+	}
+	return s.record(ctx, actorID, action, model.EntityAvailability, availabilityEntityID(ref), ref.EventID, b, a)
+}
+
+func availabilityEntityID(av *model.Availability) string {
+	return av.EventID + "/" + av.UserID
+}
+
+func marshalState(v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// diffFields compares two JSON objects field by field.
+func diffFields(before, after json.RawMessage) ([]model.FieldChange, error) {
+	b, err := decodeFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := decodeFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]struct{}, len(a)+len(b))
+	for f := range b {
+		fields[f] = struct{}{}
+	}
+	for f := range a {
+		fields[f] = struct{}{}
+	}
+	names := make([]string, 0, len(fields))
+	for f := range fields {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+
+	var changes []model.FieldChange
+	for _, f := range names {
+		if !bytes.Equal(b[f], a[f]) {
+			changes = append(changes, model.FieldChange{Field: f, Before: b[f], After: a[f]})
+		}
+	}
+	return changes, nil
+}
+
+func decodeFields(raw json.RawMessage) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if len(raw) == 0 {
+		return fields, nil
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
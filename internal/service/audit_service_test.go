@@ -0,0 +1,64 @@
+package service_test
+
+import (
+	"errors"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHistory_RecordsMutations(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	event := &model.Event{
+		ID: "e1", Title: "Planning", DurationMin: 60, Participants: participants(2),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", event))
+
+	updated := *event
+	updated.Slots = nil
+	require.NoError(t, svc.UpdateEvent(t.Context(), "u1", &updated))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u2", &model.Availability{EventID: "e1"}))
+	require.NoError(t, svc.DeleteAvailability(t.Context(), "u2", "e1", "u2"))
+
+	history, err := svc.GetEventHistory(t.Context(), "e1")
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+
+	assert.Equal(t, model.AuditCreate, history[0].Action)
+	assert.Nil(t, history[0].Before)
+
+	slotsChange := history[1]
+	assert.Equal(t, "u1", slotsChange.Actor)
+	assert.Equal(t, model.AuditUpdate, slotsChange.Action)
+	require.Len(t, slotsChange.Changes, 1)
+	assert.Equal(t, "slots", slotsChange.Changes[0].Field)
+	assert.JSONEq(t, "null", string(slotsChange.Changes[0].After))
+
+	assert.Equal(t, model.EntityAvailability, history[2].Entity)
+	assert.Equal(t, "e1/u2", history[2].EntityID)
+	assert.Equal(t, model.AuditDelete, history[3].Action)
+	assert.Nil(t, history[3].After)
+}
+
+func TestQueryAudit_AdminOnly(t *testing.T) {
+	svc := service.NewSchedulerService(
+		repository.NewInMemoryUserRepository(),
+		repository.NewInMemoryEventRepository(),
+		repository.NewInMemoryAvailabilityRepository(),
+		service.WithAdmins("root"),
+	)
+	require.NoError(t, svc.CreateUser(t.Context(), &model.User{ID: "u1"}))
+
+	_, err := svc.QueryAudit(t.Context(), "u1", model.AuditFilter{})
+	assert.True(t, errors.Is(err, service.ErrForbidden))
+
+	entries, err := svc.QueryAudit(t.Context(), "root", model.AuditFilter{Entity: model.EntityUser})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "u1", entries[0].Actor)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+)
+
+const apiKeyPrefix = "msk_"
+
+// RegisterUser creates a user and issues their first API key. If the key
+// cannot be issued, the user is not created either.
+func (s *SchedulerService) RegisterUser(ctx context.Context, u *model.User) (*model.Registration, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.RegisterUser")
+	defer span.End()
+	var cred *model.IssuedCredential
+	err := s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		if err := tx.createUser(ctx, u); err != nil {
+			return err
+		}
+		var err error
+		cred, err = tx.issueAPIKey(ctx, u.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.Registration{User: u, Credential: cred}, nil
+}
+
+// IssueAPIKey creates a new API key for the user. The returned key is not
+// stored and cannot be recovered later.
+func (s *SchedulerService) IssueAPIKey(ctx context.Context, userID string) (*model.IssuedCredential, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.IssueAPIKey")
+	defer span.End()
+	var cred *model.IssuedCredential
+	err := s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		var err error
+		cred, err = tx.issueAPIKey(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// issueAPIKey must run inside atomically.
+func (s *SchedulerService) issueAPIKey(ctx context.Context, userID string) (*model.IssuedCredential, error) {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	id, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + secret
+	cred := model.Credential{ID: id, UserID: userID, KeyHash: hashKey(key), CreatedAt: s.now()}
+	if err := s.credentialRepo.Create(ctx, &cred); err != nil {
+		return nil, err
+	}
+	return &model.IssuedCredential{Credential: cred, Key: key}, nil
+}
+
+func (s *SchedulerService) ListAPIKeys(ctx context.Context, userID string) ([]*model.Credential, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.ListAPIKeys")
+	defer span.End()
+	return s.credentialRepo.ListByUser(ctx, userID)
+}
+
+// RevokeAPIKey deletes one of the user's own API keys.
+func (s *SchedulerService) RevokeAPIKey(ctx context.Context, userID, credentialID string) error {
+	ctx, span := tracing.Start(ctx, "SchedulerService.RevokeAPIKey")
+	defer span.End()
+	creds, err := s.credentialRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, cred := range creds {
+		if cred.ID == credentialID {
+			return s.credentialRepo.Delete(ctx, credentialID)
+		}
+	}
+	return fmt.Errorf("api key %s not found", credentialID)
+}
+
+// Authenticate resolves an API key to the user it was issued to.
+func (s *SchedulerService) Authenticate(ctx context.Context, key string) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.Authenticate")
+	defer span.End()
+	if key == "" {
+		return nil, ErrUnauthenticated
+	}
+	cred, err := s.credentialRepo.GetByKeyHash(ctx, hashKey(key))
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	user, err := s.userRepo.Get(ctx, cred.UserID)
+	if err != nil || user == nil {
+		return nil, ErrUnauthenticated
+	}
+	return user, nil
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
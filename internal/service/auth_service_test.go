@@ -0,0 +1,108 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterUser_IssuesWorkingKey(t *testing.T) {
+	svc := newInMemoryService(t, 0)
+
+	registration, err := svc.RegisterUser(t.Context(), &model.User{ID: "alice", Name: "Alice"})
+	require.NoError(t, err)
+	require.NotEmpty(t, registration.Credential.Key)
+	assert.Equal(t, "alice", registration.Credential.UserID)
+
+	user, err := svc.Authenticate(t.Context(), registration.Credential.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user.ID)
+}
+
+// failingCredentials rejects every new credential.
+type failingCredentials struct{ repository.CredentialRepository }
+
+func (failingCredentials) Create(context.Context, *model.Credential) error {
+	return errors.New("disk full")
+}
+
+func TestRegisterUser_NoUserWithoutKey(t *testing.T) {
+	svc := newInMemoryService(t, 0, service.WithCredentials(failingCredentials{repository.NewInMemoryCredentialRepository()}))
+
+	_, err := svc.RegisterUser(t.Context(), &model.User{ID: "alice", Name: "Alice"})
+	assert.EqualError(t, err, "disk full")
+	_, err = svc.GetUser(t.Context(), "alice")
+	assert.Error(t, err, "the user is rolled back with the key")
+}
+
+func TestRegisterUser_ConcurrentSignUpsWithOneID(t *testing.T) {
+	svc := newInMemoryService(t, 0)
+
+	var wg sync.WaitGroup
+	var registered atomic.Int32
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := svc.RegisterUser(t.Context(), &model.User{ID: "alice", Name: "Alice"}); err == nil {
+				registered.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), registered.Load())
+	keys, err := svc.ListAPIKeys(t.Context(), "alice")
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
+
+func TestAuthenticate_InvalidKey(t *testing.T) {
+	svc := newInMemoryService(t, 0)
+
+	for _, key := range []string{"", "msk_unknown"} {
+		_, err := svc.Authenticate(t.Context(), key)
+		assert.True(t, errors.Is(err, service.ErrUnauthenticated))
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+
+	cred, err := svc.IssueAPIKey(t.Context(), "u1")
+	require.NoError(t, err)
+	keys, err := svc.ListAPIKeys(t.Context(), "u1")
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	// Other users cannot revoke someone else's key.
+	assert.Error(t, svc.RevokeAPIKey(t.Context(), "u2", cred.ID))
+
+	require.NoError(t, svc.RevokeAPIKey(t.Context(), "u1", cred.ID))
+	_, err = svc.Authenticate(t.Context(), cred.Key)
+	assert.True(t, errors.Is(err, service.ErrUnauthenticated))
+}
+
+func TestAvailability_ActorMustMatchUser(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", DurationMin: 60, Participants: participants(2)}))
+
+	err := svc.AddAvailability(t.Context(), "u1", &model.Availability{EventID: "e1", UserID: "u2"})
+	assert.True(t, errors.Is(err, service.ErrForbidden))
+
+	// An empty UserID is filled in from the actor.
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{EventID: "e1"}))
+	av, err := svc.GetAvailability(t.Context(), "e1", "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "u1", av.UserID)
+
+	err = svc.DeleteAvailability(t.Context(), "u2", "e1", "u1")
+	assert.True(t, errors.Is(err, service.ErrForbidden))
+}
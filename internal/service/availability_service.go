@@ -1,30 +1,206 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"meeting-scheduler/internal/logging"
 	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
 )
 
-func (s *SchedulerService) GetAvailability(eventID, userID string) (model.Availability, error) {
-	if err := s.validateUserAndEventExistByIDs(eventID, userID); err != nil {
+func (s *SchedulerService) GetAvailability(ctx context.Context, eventID, userID string) (model.Availability, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.GetAvailability", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	if err := s.validateUserAndEventExistByIDs(ctx, eventID, userID); err != nil {
 		return model.Availability{}, err
 	}
-	return s.availabilityRepo.Get(eventID, userID)
+	return s.availabilityRepo.Get(ctx, eventID, userID)
 }
 
-func (s *SchedulerService) AddAvailability(av model.Availability) error {
-	if err := s.validateUserAndEventExist(av); err != nil {
+// AddAvailability stores availability on behalf of actorID. The availability
+// is always recorded for the actor; a different UserID in av is rejected.
+func (s *SchedulerService) AddAvailability(ctx context.Context, actorID string, av *model.Availability) error {
+	ctx, span := tracing.Start(ctx, "SchedulerService.AddAvailability", tracing.AttrEventID.String(av.EventID))
+	defer span.End()
+	logging.AddAttrs(ctx, slog.String(logging.KeyEventID, av.EventID))
+	if err := bindActor(actorID, av); err != nil {
 		return err
 	}
-	return s.availabilityRepo.Create(av)
+	if err := validateDeclined(*av); err != nil {
+		return err
+	}
+	av.UpdatedAt = s.now()
+	return s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		if err := tx.validateParticipant(ctx, *av); err != nil {
+			return err
+		}
+		if err := tx.availabilityRepo.Create(ctx, *av); err != nil {
+			return err
+		}
+		return tx.recordAvailability(ctx, actorID, model.AuditCreate, nil, av)
+	})
 }
 
-func (s *SchedulerService) UpdateAvailability(av model.Availability) error {
-	if err := s.validateUserAndEventExist(av); err != nil {
+func (s *SchedulerService) UpdateAvailability(ctx context.Context, actorID string, av *model.Availability) error {
+	ctx, span := tracing.Start(ctx, "SchedulerService.UpdateAvailability", tracing.AttrEventID.String(av.EventID))
+	defer span.End()
+	logging.AddAttrs(ctx, slog.String(logging.KeyEventID, av.EventID))
+	if err := bindActor(actorID, av); err != nil {
 		return err
 	}
-	return s.availabilityRepo.Update(av)
+	if err := validateDeclined(*av); err != nil {
+		return err
+	}
+	av.UpdatedAt = s.now()
+	return s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		if err := tx.validateParticipant(ctx, *av); err != nil {
+			return err
+		}
+		before, err := tx.availabilityRepo.Get(ctx, av.EventID, av.UserID)
+		if err != nil {
+			return err
+		}
+		if err := tx.availabilityRepo.Update(ctx, *av); err != nil {
+			return err
+		}
+		return tx.recordAvailability(ctx, actorID, model.AuditUpdate, &before, av)
+	})
+}
+
+// SetAvailability creates or replaces the availability of actorID, who
+// must be the UserID of av, and reports whether it was created.
+func (s *SchedulerService) SetAvailability(ctx context.Context, actorID string, av *model.Availability) (created bool, err error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.SetAvailability", tracing.AttrEventID.String(av.EventID))
+	defer span.End()
+	logging.AddAttrs(ctx, slog.String(logging.KeyEventID, av.EventID))
+	if err := bindActor(actorID, av); err != nil {
+		return false, err
+	}
+	if err := validateDeclined(*av); err != nil {
+		return false, err
+	}
+	av.UpdatedAt = s.now()
+	err = s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		if err := tx.validateParticipant(ctx, *av); err != nil {
+			return err
+		}
+		created, err = tx.upsertAvailability(ctx, actorID, *av)
+		return err
+	})
+	return created && err == nil, err
+}
+
+func (s *SchedulerService) DeleteAvailability(ctx context.Context, actorID, eventID, userID string) error {
+	ctx, span := tracing.Start(ctx, "SchedulerService.DeleteAvailability", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	if actorID != userID {
+		return fmt.Errorf("%w: users may only remove their own availability", ErrForbidden)
+	}
+	return s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		before, err := tx.availabilityRepo.Get(ctx, eventID, userID)
+		if err != nil {
+			return err
+		}
+		if err := tx.availabilityRepo.Delete(ctx, eventID, userID); err != nil {
+			return err
+		}
+		return tx.recordAvailability(ctx, actorID, model.AuditDelete, &before, nil)
+	})
+}
+
+// DeclineEvent records that the participant userID will not attend. Any
+// availability they submitted earlier is replaced.
+func (s *SchedulerService) DeclineEvent(ctx context.Context, eventID, userID string) (model.Availability, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.DeclineEvent", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	av := model.Availability{EventID: eventID, UserID: userID, Declined: true, UpdatedAt: s.now()}
+	err := s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		event, err := tx.ensureEventExists(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if err := ensureParticipant(event, userID); err != nil {
+			return err
+		}
+		_, err = tx.upsertAvailability(ctx, userID, av)
+		return err
+	})
+	if err != nil {
+		return model.Availability{}, err
+	}
+	return av, nil
 }
 
-func (s *SchedulerService) DeleteAvailability(eventID, userID string) error {
-	return s.availabilityRepo.Delete(eventID, userID)
+// upsertAvailability creates or replaces av and records the change. It
+// reports whether av was created. It must run inside atomically.
+func (s *SchedulerService) upsertAvailability(ctx context.Context, actorID string, av model.Availability) (created bool, err error) {
+	if existing, err := s.availabilityRepo.Get(ctx, av.EventID, av.UserID); err == nil {
+		if err := s.availabilityRepo.Update(ctx, av); err != nil {
+			return false, err
+		}
+		return false, s.recordAvailability(ctx, actorID, model.AuditUpdate, &existing, &av)
+	}
+	if err := s.availabilityRepo.Create(ctx, av); err != nil {
+		return false, err
+	}
+	return true, s.recordAvailability(ctx, actorID, model.AuditCreate, nil, &av)
+}
+
+// GetResponseSummary groups the event's participants into responded,
+// declined and pending, in participant order.
+func (s *SchedulerService) GetResponseSummary(ctx context.Context, eventID string) (*model.ResponseSummary, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.GetResponseSummary", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	event, err := s.ensureEventExists(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	availMap, err := s.availabilityRepo.GetByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	summary := &model.ResponseSummary{
+		EventID:   eventID,
+		Responded: []model.ParticipantResponse{},
+		Declined:  []model.ParticipantResponse{},
+		Pending:   []model.ParticipantResponse{},
+	}
+	for _, userID := range allParticipants(event) {
+		av, ok := availMap[userID]
+		if !ok {
+			summary.Pending = append(summary.Pending, model.ParticipantResponse{UserID: userID})
+			continue
+		}
+		updatedAt := av.UpdatedAt
+		response := model.ParticipantResponse{UserID: userID, UpdatedAt: &updatedAt}
+		if av.Declined {
+			summary.Declined = append(summary.Declined, response)
+		} else {
+			summary.Responded = append(summary.Responded, response)
+		}
+	}
+	return summary, nil
+}
+
+// bindActor fills in the availability's user from the authenticated actor and
+// rejects attempts to write someone else's availability.
+func bindActor(actorID string, av *model.Availability) error {
+	if av.UserID == "" {
+		av.UserID = actorID
+	}
+	if av.UserID != actorID {
+		return fmt.Errorf("%w: users may only modify their own availability", ErrForbidden)
+	}
+	return nil
+}
+
+// validateDeclined rejects availability that both declines the event and
+// offers slots for it.
+func validateDeclined(av model.Availability) error {
+	if av.Declined && len(av.Slots) > 0 {
+		return fmt.Errorf("declined availability cannot list available slots")
+	}
+	return nil
 }
@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetResponseSummary(t *testing.T) {
+	svc := newInMemoryService(t, 3)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(3),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{
+		EventID: "e1", UserID: "u1", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+	}))
+	_, err := svc.DeclineEvent(t.Context(), "e1", "u2")
+	require.NoError(t, err)
+
+	summary, err := svc.GetResponseSummary(t.Context(), "e1")
+	require.NoError(t, err)
+
+	require.Len(t, summary.Responded, 1)
+	assert.Equal(t, "u1", summary.Responded[0].UserID)
+	require.NotNil(t, summary.Responded[0].UpdatedAt)
+	assert.False(t, summary.Responded[0].UpdatedAt.IsZero())
+	require.Len(t, summary.Declined, 1)
+	assert.Equal(t, "u2", summary.Declined[0].UserID)
+	assert.Equal(t, []model.ParticipantResponse{{UserID: "u3"}}, summary.Pending)
+}
+
+func TestDeclineEvent_ExcludedFromUnavailable(t *testing.T) {
+	svc := newInMemoryService(t, 3)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(3),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{
+		EventID: "e1", UserID: "u1", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u2", &model.Availability{
+		EventID: "e1", UserID: "u2", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+	}))
+
+	// Declining replaces the availability u2 submitted earlier.
+	av, err := svc.DeclineEvent(t.Context(), "e1", "u2")
+	require.NoError(t, err)
+	assert.True(t, av.Declined)
+	assert.Empty(t, av.Slots)
+
+	result, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	require.Len(t, result.SuggestedSlots, 1)
+	assert.Equal(t, []string{"u3"}, result.SuggestedSlots[0].UnavailableUsers)
+	assert.Equal(t, []string{"u2"}, result.SuggestedSlots[0].DeclinedUsers)
+}
+
+func TestAvailability_DeclinedWithSlots(t *testing.T) {
+	users := repository.NewInMemoryUserRepository()
+	availability := repository.NewInMemoryAvailabilityRepository()
+	svc := service.NewSchedulerService(users, repository.NewInMemoryEventRepository(), availability)
+	for _, id := range participants(2) {
+		require.NoError(t, svc.CreateUser(t.Context(), &model.User{ID: id, Name: id}))
+	}
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(2),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+	}))
+	slots := []model.Slot{{Start: at(9, 0), End: at(10, 0)}}
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{EventID: "e1", Slots: slots}))
+
+	declined := func() *model.Availability { return &model.Availability{EventID: "e1", Declined: true, Slots: slots} }
+	assert.EqualError(t, svc.AddAvailability(t.Context(), "u2", declined()), "declined availability cannot list available slots")
+	assert.EqualError(t, svc.UpdateAvailability(t.Context(), "u1", declined()), "declined availability cannot list available slots")
+	_, err := svc.SetAvailability(t.Context(), "u2", declined())
+	assert.EqualError(t, err, "declined availability cannot list available slots")
+
+	// Stored before the check existed, such availability still counts as
+	// a refusal.
+	require.NoError(t, availability.Create(t.Context(), model.Availability{EventID: "e1", UserID: "u2", Declined: true, Slots: slots}))
+	result, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.BestAttendance)
+	require.Len(t, result.SuggestedSlots, 1)
+	assert.Equal(t, []string{"u2"}, result.SuggestedSlots[0].DeclinedUsers)
+	explanation, err := svc.ExplainWindow(t.Context(), "e1", at(9, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 1, explanation.Score.Available)
+	assert.Equal(t, 1, explanation.Score.Declined)
+	assert.Equal(t, 1, explanation.WinnerAttendance)
+}
+
+func TestDeclineEvent_NotParticipant(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", DurationMin: 60, Participants: []string{"u1"}}))
+
+	_, err := svc.DeclineEvent(t.Context(), "e1", "u2")
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	assert.ErrorContains(t, err, "user u2 is not a participant of event e1")
+}
+
+func TestAvailability_OutsidersRejected(t *testing.T) {
+	svc := newInMemoryService(t, 3)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(2),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+	before, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+
+	slots := []model.Slot{{Start: at(9, 0), End: at(10, 0)}}
+	err = svc.AddAvailability(t.Context(), "u3", &model.Availability{EventID: "e1", Slots: slots})
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	err = svc.UpdateAvailability(t.Context(), "u3", &model.Availability{EventID: "e1", Slots: slots})
+	assert.ErrorIs(t, err, service.ErrForbidden)
+	_, err = svc.SetAvailability(t.Context(), "u3", &model.Availability{EventID: "e1", Slots: slots})
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	after, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Equal(t, before, after, "the outsider changes no suggestion")
+}
+
+func TestSetAvailability_CreatesThenReplaces(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(2),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+
+	created, err := svc.SetAvailability(t.Context(), "u2", &model.Availability{
+		EventID: "e1", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+	})
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = svc.SetAvailability(t.Context(), "u2", &model.Availability{EventID: "e1", UserID: "u2", Declined: true})
+	require.NoError(t, err)
+	assert.False(t, created)
+	av, err := svc.GetAvailability(t.Context(), "e1", "u2")
+	require.NoError(t, err)
+	assert.True(t, av.Declined)
+	assert.Empty(t, av.Slots)
+
+	_, err = svc.SetAvailability(t.Context(), "u1", &model.Availability{EventID: "e1", UserID: "u2"})
+	assert.ErrorIs(t, err, service.ErrForbidden)
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"sort"
+)
+
+// batchSearchBudget caps the number of search nodes ScheduleBatch visits so
+// that large batches return the best assignment found instead of hanging.
+const batchSearchBudget = 200000
+
+type batchItem struct {
+	event   *model.Event
+	options []scoredWindow // windows meeting quorum, best attendance first
+	active  []string       // participants who have not declined
+}
+
+// ScheduleBatch assigns each event a window from its candidate slots such that
+// events sharing a participant never overlap. It places as many events as
+// possible and, among those assignments, maximizes total attendance.
+func (s *SchedulerService) ScheduleBatch(ctx context.Context, eventIDs []string) (*model.BatchSchedule, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.ScheduleBatch", tracing.AttrBatchEvents.Int(len(eventIDs)))
+	defer span.End()
+	if len(eventIDs) == 0 {
+		return nil, fmt.Errorf("at least one event ID is required")
+	}
+
+	schedule := &model.BatchSchedule{
+		Scheduled: []model.ScheduledEvent{},
+		Unplaced:  []model.UnplacedEvent{},
+	}
+	unplaced := make(map[string]string)
+	seen := make(map[string]struct{}, len(eventIDs))
+	var items []batchItem
+
+	for _, id := range eventIDs {
+		if _, ok := seen[id]; ok {
+			return nil, fmt.Errorf("event %s is listed more than once", id)
+		}
+		seen[id] = struct{}{}
+
+		event, err := s.ensureEventExists(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		required := requiredAttendance(event)
+		availMap, err := s.availabilityRepo.GetByEvent(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		active, _ := partitionParticipants(event, availMap)
+		windows, err := s.scoreWindows(ctx, event, availMap)
+		if err != nil {
+			return nil, err
+		}
+		var options []scoredWindow
+		for _, w := range windows {
+			if len(w.available) >= required {
+				options = append(options, w)
+			}
+		}
+		if len(options) == 0 {
+			unplaced[id] = "no window meets the event's quorum"
+			continue
+		}
+		sort.SliceStable(options, func(i, j int) bool {
+			return len(options[i].available) > len(options[j].available)
+		})
+		items = append(items, batchItem{event: event, options: options, active: active})
+	}
+
+	// Events with fewer options are the most constrained, so place them first.
+	sort.SliceStable(items, func(i, j int) bool {
+		return len(items[i].options) < len(items[j].options)
+	})
+
+	search := newBatchSearch(ctx, items)
+	search.run(0, 0, 0)
+	if search.err != nil {
+		return nil, search.err
+	}
+
+	placed := make(map[string]model.ScheduledEvent, len(items))
+	for i, item := range items {
+		k := search.best[i]
+		if k < 0 {
+			unplaced[item.event.ID] = "conflicts with other events in the batch"
+			continue
+		}
+		w := item.options[k]
+		attendees := append([]string(nil), w.available...)
+		sort.Strings(attendees)
+		placed[item.event.ID] = model.ScheduledEvent{
+			EventID:          item.event.ID,
+			Slot:             w.slot,
+			Attendees:        attendees,
+			UnavailableUsers: getMissingUsers2(item.active, w.available),
+		}
+		schedule.TotalAttendance += len(w.available)
+	}
+
+	for _, id := range eventIDs {
+		if scheduled, ok := placed[id]; ok {
+			schedule.Scheduled = append(schedule.Scheduled, scheduled)
+		} else {
+			schedule.Unplaced = append(schedule.Unplaced, model.UnplacedEvent{EventID: id, Reason: unplaced[id]})
+		}
+	}
+	return schedule, nil
+}
+
+// batchSearch is a branch and bound search over window choices. choice[i] is
+// the option index picked for items[i], or -1 when the event is left unplaced.
+type batchSearch struct {
+	ctx            context.Context
+	err            error
+	items          []batchItem
+	shared         [][]bool
+	suffixMax      []int
+	choice         []int
+	best           []int
+	bestPlaced     int
+	bestAttendance int
+	budget         int
+}
+
+func newBatchSearch(ctx context.Context, items []batchItem) *batchSearch {
+	b := &batchSearch{
+		ctx:        ctx,
+		items:      items,
+		shared:     make([][]bool, len(items)),
+		suffixMax:  make([]int, len(items)+1),
+		choice:     make([]int, len(items)),
+		best:       make([]int, len(items)),
+		bestPlaced: -1,
+		budget:     batchSearchBudget,
+	}
+	for i := range items {
+		b.shared[i] = make([]bool, len(items))
+		for j := range items {
+			b.shared[i][j] = i != j && sharesParticipant(items[i].event, items[j].event)
+		}
+		b.best[i] = -1
+	}
+	for i := len(items) - 1; i >= 0; i-- {
+		b.suffixMax[i] = b.suffixMax[i+1] + len(items[i].options[0].available)
+	}
+	return b
+}
+
+func (b *batchSearch) run(i, placed, attendance int) {
+	if b.err != nil || b.budget <= 0 {
+		return
+	}
+	b.budget--
+	if b.budget%cancelCheckInterval == 0 {
+		if b.err = b.ctx.Err(); b.err != nil {
+			return
+		}
+	}
+
+	if i == len(b.items) {
+		if placed > b.bestPlaced || (placed == b.bestPlaced && attendance > b.bestAttendance) {
+			b.bestPlaced, b.bestAttendance = placed, attendance
+			copy(b.best, b.choice)
+		}
+		return
+	}
+
+	remaining := len(b.items) - i
+	if placed+remaining < b.bestPlaced ||
+		(placed+remaining == b.bestPlaced && attendance+b.suffixMax[i] <= b.bestAttendance) {
+		return
+	}
+
+	for k, opt := range b.items[i].options {
+		if b.conflicts(i, opt.slot) {
+			continue
+		}
+		b.choice[i] = k
+		b.run(i+1, placed+1, attendance+len(opt.available))
+	}
+	b.choice[i] = -1
+	b.run(i+1, placed, attendance)
+}
+
+func (b *batchSearch) conflicts(i int, slot model.Slot) bool {
+	for j := 0; j < i; j++ {
+		if b.choice[j] < 0 || !b.shared[i][j] {
+			continue
+		}
+		if slotsOverlap(slot, b.items[j].options[b.choice[j]].slot) {
+			return true
+		}
+	}
+	return false
+}
+
+func sharesParticipant(a, b *model.Event) bool {
+	set := make(map[string]struct{}, len(a.Participants))
+	for _, p := range allParticipants(a) {
+		set[p] = struct{}{}
+	}
+	for _, p := range allParticipants(b) {
+		if _, ok := set[p]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func slotsOverlap(a, b model.Slot) bool {
+	return a.Start.Before(b.End) && b.Start.Before(a.End)
+}
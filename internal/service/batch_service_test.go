@@ -0,0 +1,61 @@
+package service_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleBatch_AvoidsOverlapForSharedParticipants(t *testing.T) {
+	svc := newInMemoryService(t, 3)
+	slots := []model.Slot{{Start: at(9, 0), End: at(11, 0)}}
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "a", DurationMin: 60, Participants: []string{"u1", "u2"}, Slots: slots}))
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "b", DurationMin: 60, Participants: []string{"u1", "u3"}, Slots: slots}))
+
+	// u1 is free all morning, u2 only from 9 to 10 and u3 all morning.
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{EventID: "a", UserID: "u1", Slots: slots}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u2", &model.Availability{EventID: "a", UserID: "u2", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}}}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{EventID: "b", UserID: "u1", Slots: slots}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u3", &model.Availability{EventID: "b", UserID: "u3", Slots: slots}))
+
+	schedule, err := svc.ScheduleBatch(t.Context(), []string{"a", "b"})
+	require.NoError(t, err)
+
+	require.Len(t, schedule.Scheduled, 2)
+	assert.Empty(t, schedule.Unplaced)
+	assert.Equal(t, 4, schedule.TotalAttendance)
+	assert.Equal(t, "a", schedule.Scheduled[0].EventID)
+	assert.Equal(t, at(9, 0), schedule.Scheduled[0].Slot.Start)
+	assert.Equal(t, "b", schedule.Scheduled[1].EventID)
+	assert.Equal(t, at(10, 0), schedule.Scheduled[1].Slot.Start)
+}
+
+func TestScheduleBatch_ReportsUnplaceableEvents(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	slot := []model.Slot{{Start: at(9, 0), End: at(10, 0)}}
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "a", DurationMin: 60, Participants: []string{"u1"}, Slots: slot}))
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "b", DurationMin: 60, Participants: []string{"u1"}, Slots: slot}))
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "c", DurationMin: 60, Participants: []string{"u2"}, Slots: slot}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{EventID: "a", UserID: "u1", Slots: slot}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{EventID: "b", UserID: "u1", Slots: slot}))
+
+	schedule, err := svc.ScheduleBatch(t.Context(), []string{"a", "b", "c"})
+	require.NoError(t, err)
+
+	require.Len(t, schedule.Scheduled, 1)
+	require.Len(t, schedule.Unplaced, 2)
+	assert.Equal(t, model.UnplacedEvent{EventID: "c", Reason: "no window meets the event's quorum"}, schedule.Unplaced[1])
+	assert.Equal(t, "conflicts with other events in the batch", schedule.Unplaced[0].Reason)
+}
+
+func TestScheduleBatch_InvalidInput(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+
+	_, err := svc.ScheduleBatch(t.Context(), nil)
+	assert.Error(t, err)
+
+	_, err = svc.ScheduleBatch(t.Context(), []string{"missing"})
+	assert.Error(t, err)
+}
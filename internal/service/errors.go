@@ -0,0 +1,12 @@
+package service
+
+import "errors"
+
+var (
+	// ErrUnauthenticated is returned when a request carries no valid credential.
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden is returned when the caller may not act on a resource.
+	ErrForbidden = errors.New("forbidden")
+	// ErrNotFound is returned when a resource the request names does not exist.
+	ErrNotFound = errors.New("not found")
+)
@@ -1,50 +1,239 @@
 package service
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"meeting-scheduler/internal/logging"
 	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"slices"
+	"sort"
+	"time"
 )
 
-func (s *SchedulerService) GetEvent(id string) (*model.Event, error) {
-	event, err := s.eventRepo.Get(id)
+func (s *SchedulerService) GetEvent(ctx context.Context, id string) (*model.Event, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.GetEvent", tracing.AttrEventID.String(id))
+	defer span.End()
+	event, err := s.eventRepo.Get(ctx, id)
 	if err != nil || event == nil {
-		return nil, fmt.Errorf("event with ID %s not found", id)
+		return nil, errEventNotFound(id)
 	}
 	return event, nil
 }
 
-func (s *SchedulerService) CreateEvent(e *model.Event) error {
-	if len(e.Participants) == 0 {
-		return fmt.Errorf("event must have at least one participant")
-	}
-	if err := s.ensureUsersExist(e.Participants...); err != nil {
-		return err
+// ListEvents returns the events the actor organizes, co-organizes or takes
+// part in, ordered by ID.
+func (s *SchedulerService) ListEvents(ctx context.Context, actorID string) ([]*model.Event, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.ListEvents")
+	defer span.End()
+	all, err := s.eventRepo.List(ctx)
+	if err != nil {
+		return nil, err
 	}
-	if existing, _ := s.eventRepo.Get(e.ID); existing != nil {
-		return fmt.Errorf("event with ID %s already exists", e.ID)
+	events := []*model.Event{}
+	for _, e := range all {
+		if authorizeOrganizer(e, actorID) == nil || slices.Contains(e.Participants, actorID) {
+			events = append(events, e)
+		}
 	}
-	return s.eventRepo.Create(e)
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
 }
 
-func (s *SchedulerService) UpdateEvent(e *model.Event) error {
+// CreateEvent stores a new event organized by actorID. It is never created
+// in the trash or finalized; DeleteEvent and FinalizeEvent are the ways
+// there.
+func (s *SchedulerService) CreateEvent(ctx context.Context, actorID string, e *model.Event) error {
+	ctx, span := tracing.Start(ctx, "SchedulerService.CreateEvent", tracing.AttrEventID.String(e.ID))
+	defer span.End()
+	logging.AddAttrs(ctx, slog.String(logging.KeyEventID, e.ID))
 	if len(e.Participants) == 0 {
 		return fmt.Errorf("event must have at least one participant")
 	}
-	if err := s.ensureUsersExist(e.Participants...); err != nil {
+	if err := s.ensureUsersExist(ctx, e.Participants...); err != nil {
+		return err
+	}
+	if err := s.ensureUsersExist(ctx, e.CoOrganizers...); err != nil {
+		return err
+	}
+	if err := validateQuorum(e); err != nil {
 		return err
 	}
-	if existing, err := s.eventRepo.Get(e.ID); err != nil || existing == nil {
-		return fmt.Errorf("event with ID %s does not exist", e.ID)
+	if err := validateSplit(e); err != nil {
+		return err
 	}
-	return s.eventRepo.Update(e)
+	e.Organizer = actorID
+	e.Guests = nil
+	e.DeletedAt = nil
+	e.FinalSessions = nil
+	return s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		if existing, _ := tx.eventRepo.Get(ctx, e.ID); existing != nil {
+			return fmt.Errorf("event with ID %s already exists", e.ID)
+		}
+		if trashed, _ := tx.eventRepo.GetTrashed(ctx, e.ID); trashed != nil {
+			return fmt.Errorf("event with ID %s is in the trash", e.ID)
+		}
+		if err := tx.eventRepo.Create(ctx, e); err != nil {
+			return err
+		}
+		return tx.record(ctx, actorID, model.AuditCreate, model.EntityEvent, e.ID, e.ID, nil, e)
+	})
+}
+
+// UpdateEvent replaces an event. Only its organizers may do so. The organizer
+// the guest list, the trash state and the final sessions cannot be changed
+// this way.
+func (s *SchedulerService) UpdateEvent(ctx context.Context, actorID string, e *model.Event) error {
+	ctx, span := tracing.Start(ctx, "SchedulerService.UpdateEvent", tracing.AttrEventID.String(e.ID))
+	defer span.End()
+	logging.AddAttrs(ctx, slog.String(logging.KeyEventID, e.ID))
+	return s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		existing, err := tx.eventRepo.Get(ctx, e.ID)
+		if err != nil || existing == nil {
+			return errEventNotFound(e.ID)
+		}
+		if err := authorizeOrganizer(existing, actorID); err != nil {
+			return err
+		}
+		e.Organizer = existing.Organizer
+		e.Guests = existing.Guests
+		e.DeletedAt = existing.DeletedAt
+		e.FinalSessions = existing.FinalSessions
+		if len(e.Participants) == 0 {
+			return fmt.Errorf("event must have at least one participant")
+		}
+		if err := tx.ensureUsersExist(ctx, e.Participants...); err != nil {
+			return err
+		}
+		if err := tx.ensureUsersExist(ctx, e.CoOrganizers...); err != nil {
+			return err
+		}
+		if err := validateQuorum(e); err != nil {
+			return err
+		}
+		if err := validateSplit(e); err != nil {
+			return err
+		}
+		if err := tx.eventRepo.Update(ctx, e); err != nil {
+			return err
+		}
+		return tx.record(ctx, actorID, model.AuditUpdate, model.EntityEvent, e.ID, e.ID, existing, e)
+	})
 }
 
-func (s *SchedulerService) DeleteEvent(id string) error {
+// DeleteEvent moves the event to the trash. It can be restored with
+// UndeleteEvent until the trash retention period has passed.
+func (s *SchedulerService) DeleteEvent(ctx context.Context, actorID, id string) error {
+	ctx, span := tracing.Start(ctx, "SchedulerService.DeleteEvent", tracing.AttrEventID.String(id))
+	defer span.End()
 	if id == "" {
 		return fmt.Errorf("event ID cannot be empty")
 	}
-	if existing, err := s.eventRepo.Get(id); err != nil || existing == nil {
-		return fmt.Errorf("event with ID %s does not exist", id)
+	return s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		existing, err := tx.eventRepo.Get(ctx, id)
+		if err != nil || existing == nil {
+			return errEventNotFound(id)
+		}
+		if err := authorizeOrganizer(existing, actorID); err != nil {
+			return err
+		}
+		if err := tx.eventRepo.Trash(ctx, id, s.now()); err != nil {
+			return err
+		}
+		return tx.record(ctx, actorID, model.AuditDelete, model.EntityEvent, id, id, existing, nil)
+	})
+}
+
+// FinalizeEvent fixes the event to the given session(s). Events without a
+// split configuration take exactly one session; split events take up to
+// MaxSessions. Sessions must lie inside the event's slots, must not overlap
+// and must add up to the event's duration.
+func (s *SchedulerService) FinalizeEvent(ctx context.Context, actorID, eventID string, sessions []model.Slot) (*model.Event, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.FinalizeEvent", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	var finalized model.Event
+	err := s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		event, err := tx.ensureEventExists(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		if err := authorizeOrganizer(event, actorID); err != nil {
+			return err
+		}
+		if err := validateSessions(event, sessions); err != nil {
+			return err
+		}
+
+		finalized = *event
+		finalized.FinalSessions = append([]model.Slot(nil), sessions...)
+		sort.Slice(finalized.FinalSessions, func(i, j int) bool {
+			return finalized.FinalSessions[i].Start.Before(finalized.FinalSessions[j].Start)
+		})
+		if err := tx.eventRepo.Update(ctx, &finalized); err != nil {
+			return err
+		}
+		return tx.record(ctx, actorID, model.AuditUpdate, model.EntityEvent, eventID, eventID, event, &finalized)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &finalized, nil
+}
+
+func validateSessions(event *model.Event, sessions []model.Slot) error {
+	maxSessions, minSession := 1, event.DurationMin
+	if event.Split != nil {
+		maxSessions, minSession = event.Split.MaxSessions, event.Split.MinSessionMin
+	}
+	if len(sessions) == 0 || len(sessions) > maxSessions {
+		return fmt.Errorf("event must be finalized with between 1 and %d sessions", maxSessions)
+	}
+
+	var total time.Duration
+	for i, session := range sessions {
+		length := session.End.Sub(session.Start)
+		if length < time.Duration(minSession)*time.Minute {
+			return fmt.Errorf("session %d is shorter than %d minutes", i+1, minSession)
+		}
+		if !isUserAvailableForExactWindow(session, event.Slots) {
+			return fmt.Errorf("session %d is outside the event's candidate slots", i+1)
+		}
+		for j := 0; j < i; j++ {
+			if slotsOverlap(session, sessions[j]) {
+				return fmt.Errorf("sessions %d and %d overlap", j+1, i+1)
+			}
+		}
+		total += length
+	}
+	if total != time.Duration(event.DurationMin)*time.Minute {
+		return fmt.Errorf("sessions add up to %d minutes, event needs %d", int(total.Minutes()), event.DurationMin)
+	}
+	return nil
+}
+
+func validateSplit(e *model.Event) error {
+	if e.Split == nil {
+		return nil
+	}
+	if e.Split.MaxSessions < 1 {
+		return fmt.Errorf("split max_sessions must be at least 1")
+	}
+	if e.Split.MinSessionMin < 1 || e.Split.MinSessionMin > e.DurationMin {
+		return fmt.Errorf("split min_session_min must be between 1 and %d", e.DurationMin)
+	}
+	return nil
+}
+
+func validateQuorum(e *model.Event) error {
+	if e.Quorum == nil {
+		return nil
+	}
+	if e.Quorum.MinCount < 0 || e.Quorum.MinCount > len(allParticipants(e)) {
+		return fmt.Errorf("quorum min_count must be between 0 and %d", len(allParticipants(e)))
+	}
+	if e.Quorum.MinPercent < 0 || e.Quorum.MinPercent > 100 {
+		return fmt.Errorf("quorum min_percent must be between 0 and 100")
 	}
-	return s.eventRepo.Delete(id)
+	return nil
 }
@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents_CannotBeTrashedByWriting(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+	deletedAt := at(9, 0)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(1), DeletedAt: &deletedAt,
+	}))
+	event, err := svc.GetEvent(t.Context(), "e1")
+	require.NoError(t, err, "a new event is live")
+	assert.Nil(t, event.DeletedAt)
+
+	require.NoError(t, svc.UpdateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", Title: "Renamed", DurationMin: 60, Participants: participants(1), DeletedAt: &deletedAt,
+	}))
+	event, err = svc.GetEvent(t.Context(), "e1")
+	require.NoError(t, err, "an update keeps the event live")
+	assert.Equal(t, "Renamed", event.Title)
+	assert.Nil(t, event.DeletedAt)
+	trashed, err := svc.ListTrash(t.Context(), "u1")
+	require.NoError(t, err)
+	assert.Empty(t, trashed)
+}
+
+func TestEvents_FinalSessionsOnlyThroughFinalize(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+	slots := []model.Slot{{Start: at(9, 0), End: at(12, 0)}}
+	bogus := []model.Slot{{Start: at(8, 0), End: at(10, 0)}, {Start: at(9, 0), End: at(13, 0)}}
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(1), Slots: slots, FinalSessions: bogus,
+	}))
+	event, err := svc.GetEvent(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Empty(t, event.FinalSessions, "a new event is not finalized")
+
+	final := []model.Slot{{Start: at(10, 0), End: at(11, 0)}}
+	_, err = svc.FinalizeEvent(t.Context(), "u1", "e1", final)
+	require.NoError(t, err)
+	require.NoError(t, svc.UpdateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", Title: "Renamed", DurationMin: 60, Participants: participants(1), Slots: slots, FinalSessions: bogus,
+	}))
+	event, err = svc.GetEvent(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", event.Title)
+	assert.Equal(t, final, event.FinalSessions, "an update keeps the finalized sessions")
+}
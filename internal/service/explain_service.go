@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"time"
+)
+
+// ExplainWindow reports, for the candidate window starting at start, each
+// participant's status, the window's score and its rank against the winner.
+func (s *SchedulerService) ExplainWindow(ctx context.Context, eventID string, start time.Time) (*model.WindowExplanation, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.ExplainWindow", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	event, err := s.ensureEventExists(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	availMap, err := s.availabilityRepo.GetByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := s.scoreWindows(ctx, event, availMap)
+	if err != nil {
+		return nil, err
+	}
+
+	target := -1
+	for i, w := range windows {
+		if w.slot.Start.Equal(start) {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return nil, fmt.Errorf("no candidate window for event %s starts at %s", eventID, start.Format(time.RFC3339))
+	}
+	window := windows[target]
+
+	explanation := &model.WindowExplanation{
+		Window:           window.slot,
+		Participants:     make([]model.ParticipantStatus, 0, len(allParticipants(event))),
+		Rank:             rankOf(windows, len(window.available)),
+		CandidateWindows: len(windows),
+	}
+
+	for _, w := range windows {
+		if explanation.Winner == nil || len(w.available) > explanation.WinnerAttendance {
+			slot := w.slot
+			explanation.Winner = &slot
+			explanation.WinnerAttendance = len(w.available)
+		}
+	}
+
+	for _, userID := range allParticipants(event) {
+		status := model.ParticipantStatus{UserID: userID}
+		av, responded := availMap[userID]
+		switch {
+		case !responded:
+			status.Status = model.StatusNoResponse
+			explanation.Score.NoResponse++
+		case av.Declined:
+			status.Status = model.StatusDeclined
+			explanation.Score.Declined++
+		case isUserAvailableForExactWindow(window.slot, av.Slots):
+			status.Status = model.StatusAvailable
+			status.CoveringSlot = coveringSlot(window.slot, av.Slots)
+			explanation.Score.Available++
+		default:
+			status.Status = model.StatusUnavailable
+			status.NearestAlternative = nearestAlternative(window.slot, windows, av.Slots)
+			explanation.Score.Unavailable++
+		}
+		explanation.Participants = append(explanation.Participants, status)
+	}
+
+	explanation.Score.RequiredAttendance = requiredAttendance(event)
+	explanation.Score.QuorumMet = len(window.available) >= explanation.Score.RequiredAttendance
+	return explanation, nil
+}
+
+// rankOf returns the dense rank of an attendance count among all windows.
+func rankOf(windows []scoredWindow, attendance int) int {
+	better := make(map[int]struct{})
+	for _, w := range windows {
+		if len(w.available) > attendance {
+			better[len(w.available)] = struct{}{}
+		}
+	}
+	return len(better) + 1
+}
+
+func coveringSlot(target model.Slot, slots []model.Slot) *model.Slot {
+	for _, s := range slots {
+		if !s.Start.After(target.Start) && !s.End.Before(target.End) {
+			slot := s
+			return &slot
+		}
+	}
+	return nil
+}
+
+// nearestAlternative finds the candidate window closest in time to target
+// that the user's availability does cover.
+func nearestAlternative(target model.Slot, windows []scoredWindow, slots []model.Slot) *model.Slot {
+	var nearest *model.Slot
+	var nearestGap time.Duration
+	for _, w := range windows {
+		if !isUserAvailableForExactWindow(w.slot, slots) {
+			continue
+		}
+		gap := w.slot.Start.Sub(target.Start)
+		if gap < 0 {
+			gap = -gap
+		}
+		if nearest == nil || gap < nearestGap {
+			slot := w.slot
+			nearest = &slot
+			nearestGap = gap
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainWindow(t *testing.T) {
+	svc := newInMemoryService(t, 3)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(3),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{
+		EventID: "e1", UserID: "u1", Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u2", &model.Availability{
+		EventID: "e1", UserID: "u2", Slots: []model.Slot{{Start: at(11, 0), End: at(12, 0)}},
+	}))
+
+	explanation, err := svc.ExplainWindow(t.Context(), "e1", at(9, 0))
+	require.NoError(t, err)
+
+	assert.Equal(t, model.Slot{Start: at(9, 0), End: at(10, 0)}, explanation.Window)
+	assert.Equal(t, 2, explanation.Rank)
+	assert.Equal(t, 9, explanation.CandidateWindows)
+	require.NotNil(t, explanation.Winner)
+	assert.Equal(t, at(11, 0), explanation.Winner.Start)
+	assert.Equal(t, 2, explanation.WinnerAttendance)
+	assert.Equal(t, model.WindowScore{
+		Available: 1, Unavailable: 1, NoResponse: 1, RequiredAttendance: 1, QuorumMet: true,
+	}, explanation.Score)
+
+	require.Len(t, explanation.Participants, 3)
+	assert.Equal(t, model.StatusAvailable, explanation.Participants[0].Status)
+	assert.Equal(t, &model.Slot{Start: at(9, 0), End: at(12, 0)}, explanation.Participants[0].CoveringSlot)
+	assert.Equal(t, model.StatusUnavailable, explanation.Participants[1].Status)
+	assert.Equal(t, &model.Slot{Start: at(11, 0), End: at(12, 0)}, explanation.Participants[1].NearestAlternative)
+	assert.Equal(t, model.StatusNoResponse, explanation.Participants[2].Status)
+}
+
+func TestExplainWindow_NotACandidate(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(1),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+
+	_, err := svc.ExplainWindow(t.Context(), "e1", at(11, 30))
+	assert.Error(t, err)
+}
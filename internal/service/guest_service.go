@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"strings"
+	"time"
+)
+
+const (
+	guestIDPrefix  = "guest_"
+	defaultLinkTTL = 7 * 24 * time.Hour
+)
+
+// linkClaims is the signed payload of a magic link token.
+type linkClaims struct {
+	LinkID  string `json:"lid"`
+	EventID string `json:"eid"`
+	GuestID string `json:"gid"`
+	Expires int64  `json:"exp"`
+}
+
+// CreateMagicLink issues a signed, expiring link that lets a guest read the
+// event and manage their own availability. A new guest is added to the event
+// unless the request names an existing one.
+func (s *SchedulerService) CreateMagicLink(ctx context.Context, actorID, eventID string, req model.MagicLinkRequest) (*model.IssuedMagicLink, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.CreateMagicLink", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	event, err := s.ensureEventExists(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOrganizer(event, actorID); err != nil {
+		return nil, err
+	}
+
+	ttl := defaultLinkTTL
+	if req.TTLMin < 0 {
+		return nil, fmt.Errorf("ttl_min cannot be negative")
+	} else if req.TTLMin > 0 {
+		ttl = time.Duration(req.TTLMin) * time.Minute
+	}
+
+	guestID := req.GuestID
+	if guestID != "" {
+		if !hasGuest(event, guestID) {
+			return nil, fmt.Errorf("guest %s is not invited to event %s", guestID, eventID)
+		}
+	} else {
+		if req.Name == "" {
+			return nil, fmt.Errorf("either guest_id or name is required")
+		}
+		suffix, err := randomHex(8)
+		if err != nil {
+			return nil, err
+		}
+		guestID = guestIDPrefix + suffix
+		// The guest is appended to the event as it is inside the
+		// transaction, so concurrent invitations all keep their guest.
+		err = s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+			current, err := tx.ensureEventExists(ctx, eventID)
+			if err != nil {
+				return err
+			}
+			if err := authorizeOrganizer(current, actorID); err != nil {
+				return err
+			}
+			updated := *current
+			updated.Guests = append(append([]model.Guest(nil), current.Guests...), model.Guest{ID: guestID, Name: req.Name})
+			if err := tx.eventRepo.Update(ctx, &updated); err != nil {
+				return err
+			}
+			return tx.record(ctx, actorID, model.AuditUpdate, model.EntityEvent, eventID, eventID, current, &updated)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	linkID, err := randomHex(8)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	link := model.MagicLink{
+		ID:        linkID,
+		EventID:   eventID,
+		GuestID:   guestID,
+		CreatedBy: actorID,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := s.linkRepo.Create(ctx, &link); err != nil {
+		return nil, err
+	}
+	token, err := s.signLink(link)
+	if err != nil {
+		return nil, err
+	}
+	return &model.IssuedMagicLink{MagicLink: link, Token: token}, nil
+}
+
+func (s *SchedulerService) ListMagicLinks(ctx context.Context, actorID, eventID string) ([]*model.MagicLink, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.ListMagicLinks", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	event, err := s.ensureEventExists(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOrganizer(event, actorID); err != nil {
+		return nil, err
+	}
+	return s.linkRepo.ListByEvent(ctx, eventID)
+}
+
+// RevokeMagicLink makes a link unusable before it expires.
+func (s *SchedulerService) RevokeMagicLink(ctx context.Context, actorID, eventID, linkID string) error {
+	ctx, span := tracing.Start(ctx, "SchedulerService.RevokeMagicLink", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	event, err := s.ensureEventExists(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := authorizeOrganizer(event, actorID); err != nil {
+		return err
+	}
+	link, err := s.linkRepo.Get(ctx, linkID)
+	if err != nil || link.EventID != eventID {
+		return fmt.Errorf("magic link %s not found for event %s", linkID, eventID)
+	}
+	revoked := *link
+	revoked.Revoked = true
+	return s.linkRepo.Update(ctx, &revoked)
+}
+
+// AuthenticateGuest verifies a magic link token and returns the link it was
+// issued for. Expired, revoked or tampered tokens yield ErrUnauthenticated.
+func (s *SchedulerService) AuthenticateGuest(ctx context.Context, token string) (*model.MagicLink, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.AuthenticateGuest")
+	defer span.End()
+	claims, err := s.verifyLink(token)
+	if err != nil {
+		return nil, ErrUnauthenticated
+	}
+	if !s.now().Before(time.Unix(claims.Expires, 0)) {
+		return nil, ErrUnauthenticated
+	}
+	link, err := s.linkRepo.Get(ctx, claims.LinkID)
+	if err != nil || link.Revoked || link.EventID != claims.EventID || link.GuestID != claims.GuestID {
+		return nil, ErrUnauthenticated
+	}
+	event, err := s.ensureEventExists(ctx, link.EventID)
+	if err != nil || !hasGuest(event, link.GuestID) {
+		return nil, ErrUnauthenticated
+	}
+	return link, nil
+}
+
+func (s *SchedulerService) GetGuestAvailability(ctx context.Context, link *model.MagicLink) (model.Availability, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.GetGuestAvailability", tracing.AttrEventID.String(link.EventID))
+	defer span.End()
+	return s.availabilityRepo.Get(ctx, link.EventID, link.GuestID)
+}
+
+// SubmitGuestAvailability creates or replaces the availability of the guest
+// the link was issued to.
+func (s *SchedulerService) SubmitGuestAvailability(ctx context.Context, link *model.MagicLink, slots []model.Slot) (*model.Availability, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.SubmitGuestAvailability", tracing.AttrEventID.String(link.EventID))
+	defer span.End()
+	av := model.Availability{EventID: link.EventID, UserID: link.GuestID, Slots: slots, UpdatedAt: s.now()}
+	err := s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		_, err := tx.upsertAvailability(ctx, link.GuestID, av)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &av, nil
+}
+
+func (s *SchedulerService) signLink(link model.MagicLink) (string, error) {
+	payload, err := json.Marshal(linkClaims{
+		LinkID:  link.ID,
+		EventID: link.EventID,
+		GuestID: link.GuestID,
+		Expires: link.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.linkMAC(encoded)), nil
+}
+
+func (s *SchedulerService) verifyLink(token string) (*linkClaims, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("malformed token")
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.linkMAC(encoded)) {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var claims linkClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+func (s *SchedulerService) linkMAC(encoded string) []byte {
+	mac := hmac.New(sha256.New, s.linkSecret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+func hasGuest(event *model.Event, guestID string) bool {
+	for _, g := range event.Guests {
+		if g.ID == guestID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMagicLink_GuestCountsAsParticipant(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(1),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+	}))
+
+	link, err := svc.CreateMagicLink(t.Context(), "u1", "e1", model.MagicLinkRequest{Name: "Visitor"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link.GuestID, "guest_"))
+
+	authed, err := svc.AuthenticateGuest(t.Context(), link.Token)
+	require.NoError(t, err)
+	_, err = svc.SubmitGuestAvailability(t.Context(), authed, []model.Slot{{Start: at(9, 0), End: at(10, 0)}})
+	require.NoError(t, err)
+
+	result, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	require.True(t, result.Viable)
+	assert.Equal(t, []string{"u1"}, result.SuggestedSlots[0].UnavailableUsers)
+}
+
+// rendezvousEvents holds the first two Get calls after arm until both have
+// read, so two writers are sure to see the same version of an event.
+type rendezvousEvents struct {
+	repository.EventRepository
+	held    atomic.Int32
+	arrived sync.WaitGroup
+}
+
+func (r *rendezvousEvents) arm() { r.held.Store(2); r.arrived.Add(2) }
+
+func (r *rendezvousEvents) Get(ctx context.Context, id string) (*model.Event, error) {
+	event, err := r.EventRepository.Get(ctx, id)
+	if r.held.Add(-1) >= 0 {
+		r.arrived.Done()
+		r.arrived.Wait()
+	}
+	return event, err
+}
+
+func TestMagicLink_ConcurrentInvitations(t *testing.T) {
+	events := &rendezvousEvents{EventRepository: repository.NewInMemoryEventRepository()}
+	svc := service.NewSchedulerService(repository.NewInMemoryUserRepository(), events, repository.NewInMemoryAvailabilityRepository())
+	require.NoError(t, svc.CreateUser(t.Context(), &model.User{ID: "u1", Name: "u1"}))
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", DurationMin: 60, Participants: participants(1)}))
+
+	events.arm()
+	var wg sync.WaitGroup
+	links := make([]*model.IssuedMagicLink, 2)
+	for i := range links {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			link, err := svc.CreateMagicLink(t.Context(), "u1", "e1", model.MagicLinkRequest{Name: fmt.Sprint("Visitor ", i)})
+			assert.NoError(t, err)
+			links[i] = link
+		}()
+	}
+	wg.Wait()
+
+	event, err := svc.GetEvent(t.Context(), "e1")
+	require.NoError(t, err)
+	for _, link := range links {
+		require.NotNil(t, link)
+		assert.True(t, slices.ContainsFunc(event.Guests, func(g model.Guest) bool { return g.ID == link.GuestID }), "guest %s was lost", link.GuestID)
+	}
+}
+
+func TestMagicLink_Rejected(t *testing.T) {
+	now := at(8, 0)
+	svc := service.NewSchedulerService(
+		repository.NewInMemoryUserRepository(),
+		repository.NewInMemoryEventRepository(),
+		repository.NewInMemoryAvailabilityRepository(),
+		service.WithClock(func() time.Time { return now }),
+	)
+	require.NoError(t, svc.CreateUser(t.Context(), &model.User{ID: "u1"}))
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", DurationMin: 60, Participants: []string{"u1"}}))
+
+	link, err := svc.CreateMagicLink(t.Context(), "u1", "e1", model.MagicLinkRequest{Name: "Visitor", TTLMin: 30})
+	require.NoError(t, err)
+
+	_, err = svc.AuthenticateGuest(t.Context(), link.Token+"x")
+	assert.True(t, errors.Is(err, service.ErrUnauthenticated), "tampered token")
+
+	now = now.Add(time.Hour)
+	_, err = svc.AuthenticateGuest(t.Context(), link.Token)
+	assert.True(t, errors.Is(err, service.ErrUnauthenticated), "expired token")
+
+	now = at(8, 0)
+	require.NoError(t, svc.RevokeMagicLink(t.Context(), "u1", "e1", link.ID))
+	_, err = svc.AuthenticateGuest(t.Context(), link.Token)
+	assert.True(t, errors.Is(err, service.ErrUnauthenticated), "revoked token")
+}
+
+func TestMagicLink_OrganizerOnly(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", DurationMin: 60, Participants: participants(2)}))
+
+	_, err := svc.CreateMagicLink(t.Context(), "u2", "e1", model.MagicLinkRequest{Name: "Visitor"})
+	assert.True(t, errors.Is(err, service.ErrForbidden))
+}
+
+func TestCreateUser_RejectsGuestPrefix(t *testing.T) {
+	svc := newInMemoryService(t, 0)
+
+	assert.Error(t, svc.CreateUser(t.Context(), &model.User{ID: "guest_123"}))
+}
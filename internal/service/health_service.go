@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"time"
+)
+
+// healthCheckTimeout bounds each component check, so one hung store cannot
+// stall the readiness probe.
+const healthCheckTimeout = 2 * time.Second
+
+type healthCheck struct {
+	name     string
+	critical bool
+	repo     any
+}
+
+// CheckHealth pings every repository concurrently and reports each one's
+// status and latency. Magic links only back guest access, so losing them
+// degrades the server rather than making it unavailable.
+func (s *SchedulerService) CheckHealth(ctx context.Context) *model.HealthReport {
+	checks := []healthCheck{
+		{"users", true, s.userRepo},
+		{"events", true, s.eventRepo},
+		{"availability", true, s.availabilityRepo},
+		{"credentials", true, s.credentialRepo},
+		{"magic_links", false, s.linkRepo},
+		{"audit", true, s.auditRepo},
+		{"versions", true, s.versionRepo},
+	}
+	report := &model.HealthReport{
+		Status:     model.HealthOK,
+		CheckedAt:  s.now(),
+		Components: make([]model.ComponentHealth, len(checks)),
+	}
+	done := make(chan struct{}, len(checks))
+	for i, check := range checks {
+		go func() {
+			report.Components[i] = checkComponent(ctx, check)
+			done <- struct{}{}
+		}()
+	}
+	for range checks {
+		<-done
+	}
+
+	for _, c := range report.Components {
+		if c.Status == model.HealthUp {
+			continue
+		}
+		if c.Critical {
+			report.Status = model.HealthUnavailable
+		} else if report.Status == model.HealthOK {
+			report.Status = model.HealthDegraded
+		}
+	}
+	return report
+}
+
+func checkComponent(ctx context.Context, check healthCheck) model.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	result := model.ComponentHealth{Name: check.name, Status: model.HealthUp, Critical: check.critical}
+	start := time.Now()
+	errc := make(chan error, 1)
+	go func() { errc <- repository.Ping(ctx, check.repo) }()
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		result.Status = model.HealthDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -1,15 +1,159 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"log/slog"
 	"meeting-scheduler/internal/repository"
+	"time"
 )
 
 type SchedulerService struct {
 	userRepo         repository.UserRepository
 	eventRepo        repository.EventRepository
 	availabilityRepo repository.AvailabilityRepository
+	credentialRepo   repository.CredentialRepository
+	linkRepo         repository.MagicLinkRepository
+	auditRepo        repository.AuditRepository
+	versionRepo      repository.VersionRepository
+	uow              repository.UnitOfWork
+	admins           map[string]struct{}
+	trashRetention   time.Duration
+	suggestionStep   time.Duration
+	linkSecret       []byte
+	logger           *slog.Logger
+	observer         Observer
+	now              func() time.Time
 }
 
-func NewSchedulerService(u repository.UserRepository, e repository.EventRepository, a repository.AvailabilityRepository) *SchedulerService {
-	return &SchedulerService{userRepo: u, eventRepo: e, availabilityRepo: a}
+// Observer is told how long expensive computations take, so they can be
+// exported as metrics. Size is participants × candidate windows.
+type Observer interface {
+	ObserveSuggestion(kind string, participants, windows int, d time.Duration)
+}
+
+type noopObserver struct{}
+
+func (noopObserver) ObserveSuggestion(string, int, int, time.Duration) {}
+
+// Option configures optional SchedulerService dependencies.
+type Option func(*SchedulerService)
+
+// WithCredentials sets the repository API keys are issued to and checked against.
+func WithCredentials(c repository.CredentialRepository) Option {
+	return func(s *SchedulerService) { s.credentialRepo = c }
+}
+
+// WithMagicLinks sets the repository guest magic links are stored in.
+func WithMagicLinks(l repository.MagicLinkRepository) Option {
+	return func(s *SchedulerService) { s.linkRepo = l }
+}
+
+// WithLinkSecret sets the HMAC key magic link tokens are signed with. Without
+// it a random key is generated and links stop working after a restart.
+func WithLinkSecret(secret []byte) Option {
+	return func(s *SchedulerService) { s.linkSecret = secret }
+}
+
+// WithAuditLog sets the append-only store every mutation is recorded in.
+func WithAuditLog(a repository.AuditRepository) Option {
+	return func(s *SchedulerService) { s.auditRepo = a }
+}
+
+// WithVersions sets the store event snapshots are kept in.
+func WithVersions(v repository.VersionRepository) Option {
+	return func(s *SchedulerService) { s.versionRepo = v }
+}
+
+// WithUnitOfWork sets how writes to users, events, availability,
+// credentials, the audit log and versions are grouped into transactions.
+// It must span the repositories the service was created with, and defaults
+// to an in-memory unit of work over them.
+func WithUnitOfWork(u repository.UnitOfWork) Option {
+	return func(s *SchedulerService) { s.uow = u }
+}
+
+// WithAdmins grants the given users access to administrative endpoints.
+func WithAdmins(userIDs ...string) Option {
+	return func(s *SchedulerService) {
+		for _, id := range userIDs {
+			s.admins[id] = struct{}{}
+		}
+	}
+}
+
+// WithTrashRetention sets how long deleted events stay in the trash before
+// the purger removes them for good.
+func WithTrashRetention(d time.Duration) Option {
+	return func(s *SchedulerService) { s.trashRetention = d }
+}
+
+// WithSuggestionStep sets how far apart the start times of candidate windows
+// are. It defaults to 15 minutes; a step that is not positive is ignored, as
+// the windows would never advance.
+func WithSuggestionStep(d time.Duration) Option {
+	return func(s *SchedulerService) {
+		if d > 0 {
+			s.suggestionStep = d
+		}
+	}
+}
+
+// WithLogger sets the logger background work and internal failures are
+// reported to. It defaults to slog.Default().
+func WithLogger(l *slog.Logger) Option {
+	return func(s *SchedulerService) { s.logger = l }
+}
+
+// WithObserver sets the receiver of computation timings.
+func WithObserver(o Observer) Option {
+	return func(s *SchedulerService) { s.observer = o }
+}
+
+// WithClock overrides the time source, mainly for tests.
+func WithClock(now func() time.Time) Option {
+	return func(s *SchedulerService) { s.now = now }
+}
+
+func NewSchedulerService(u repository.UserRepository, e repository.EventRepository, a repository.AvailabilityRepository, opts ...Option) *SchedulerService {
+	s := &SchedulerService{userRepo: u, eventRepo: e, availabilityRepo: a, admins: map[string]struct{}{}, trashRetention: defaultTrashRetention, suggestionStep: defaultSuggestionStep, logger: slog.Default(), observer: noopObserver{}, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.credentialRepo == nil {
+		s.credentialRepo = repository.NewInMemoryCredentialRepository()
+	}
+	if s.auditRepo == nil {
+		s.auditRepo = repository.NewInMemoryAuditRepository()
+	}
+	if s.versionRepo == nil {
+		s.versionRepo = repository.NewInMemoryVersionRepository()
+	}
+	if s.linkRepo == nil {
+		s.linkRepo = repository.NewInMemoryMagicLinkRepository()
+	}
+	if s.uow == nil {
+		s.uow = repository.NewInMemoryUnitOfWork(s.userRepo, s.eventRepo, s.availabilityRepo, s.credentialRepo, s.auditRepo, s.versionRepo)
+	}
+	if len(s.linkSecret) == 0 {
+		s.linkSecret = make([]byte, 32)
+		rand.Read(s.linkSecret)
+	}
+	return s
+}
+
+// atomically runs fn as one unit of work. fn gets a copy of the service whose
+// user, event, availability, credential, audit and version repositories
+// belong to the transaction, so helpers such as record read and write
+// through it. fn must
+// do its reads through that copy too, so what it records matches what it
+// changed. If fn fails, all of its writes are rolled back.
+func (s *SchedulerService) atomically(ctx context.Context, fn func(ctx context.Context, tx *SchedulerService) error) error {
+	return s.uow.Do(ctx, func(ctx context.Context, tx repository.Tx) error {
+		txs := *s
+		txs.userRepo, txs.credentialRepo = tx.Users(), tx.Credentials()
+		txs.eventRepo, txs.availabilityRepo = tx.Events(), tx.Availability()
+		txs.auditRepo, txs.versionRepo = tx.Audit(), tx.Versions()
+		return fn(ctx, &txs)
+	})
 }
@@ -1,30 +1,38 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"slices"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
-func (s *SchedulerService) validateUserAndEventExist(av model.Availability) error {
-	if err := s.ensureUsersExist(av.UserID); err != nil {
+// validateParticipant checks that the user and event of av exist and that
+// the user takes part in the event.
+func (s *SchedulerService) validateParticipant(ctx context.Context, av model.Availability) error {
+	if err := s.ensureUsersExist(ctx, av.UserID); err != nil {
 		return err
 	}
-	if _, err := s.ensureEventExists(av.EventID); err != nil {
+	event, err := s.ensureEventExists(ctx, av.EventID)
+	if err != nil {
 		return err
 	}
-	return nil
+	return ensureParticipant(event, av.UserID)
 }
 
-func (s *SchedulerService) ensureUsersExist(userIDs ...string) error {
-	missing, ok := s.validateUsersExistenceInSystem(userIDs...)
+func (s *SchedulerService) ensureUsersExist(ctx context.Context, userIDs ...string) error {
+	missing, ok := s.validateUsersExistenceInSystem(ctx, userIDs...)
 	if !ok {
 		return fmt.Errorf("missing users: %v", missing)
 	}
 	return nil
 }
 
-func (s *SchedulerService) validateUsersExistenceInSystem(userIDs ...string) ([]string, bool) {
-	allUsers, err := s.userRepo.GetAll()
+func (s *SchedulerService) validateUsersExistenceInSystem(ctx context.Context, userIDs ...string) ([]string, bool) {
+	allUsers, err := s.userRepo.GetAll(ctx)
 	if err != nil {
 		return nil, false
 	}
@@ -37,24 +45,47 @@ func (s *SchedulerService) validateUsersExistenceInSystem(userIDs ...string) ([]
 	return missing, len(missing) == 0
 }
 
-func (s *SchedulerService) ensureEventExists(eventId string) (*model.Event, error) {
-	event, _ := s.eventRepo.Get(eventId)
+func errEventNotFound(id string) error {
+	return fmt.Errorf("%w: event with ID %s does not exist", ErrNotFound, id)
+}
+
+// ensureEventExists also tags the caller's span with the event's
+// participant count.
+func (s *SchedulerService) ensureEventExists(ctx context.Context, eventId string) (*model.Event, error) {
+	event, _ := s.eventRepo.Get(ctx, eventId)
 	if event == nil {
-		return nil, fmt.Errorf("event with ID %s does not exist", eventId)
+		return nil, errEventNotFound(eventId)
 	}
+	trace.SpanFromContext(ctx).SetAttributes(tracing.AttrParticipants.Int(len(allParticipants(event))))
 	return event, nil
 }
 
-func (s *SchedulerService) validateUserAndEventExistByIDs(eventID, userID string) error {
-	if _, err := s.GetEvent(eventID); err != nil {
+func (s *SchedulerService) validateUserAndEventExistByIDs(ctx context.Context, eventID, userID string) error {
+	if _, err := s.GetEvent(ctx, eventID); err != nil {
 		return err
 	}
-	if _, err := s.GetUser(userID); err != nil {
+	if _, err := s.GetUser(ctx, userID); err != nil {
 		return err
 	}
 	return nil
 }
 
+// authorizeOrganizer allows the event's organizer and co-organizers through.
+func authorizeOrganizer(event *model.Event, actorID string) error {
+	if actorID != "" && (event.Organizer == actorID || slices.Contains(event.CoOrganizers, actorID)) {
+		return nil
+	}
+	return fmt.Errorf("%w: only organizers of event %s may change it", ErrForbidden, event.ID)
+}
+
+// ensureParticipant allows the event's participants and guests through.
+func ensureParticipant(event *model.Event, userID string) error {
+	if slices.Contains(allParticipants(event), userID) {
+		return nil
+	}
+	return fmt.Errorf("%w: user %s is not a participant of event %s", ErrForbidden, userID, event.ID)
+}
+
 func isUserAvailableForExactWindow(target model.Slot, slots []model.Slot) bool {
 	for _, s := range slots {
 		if !s.Start.After(target.Start) && !s.End.Before(target.End) {
@@ -64,6 +95,29 @@ func isUserAvailableForExactWindow(target model.Slot, slots []model.Slot) bool {
 	return false
 }
 
+// allParticipants lists the event's registered participants followed by its
+// guests.
+func allParticipants(event *model.Event) []string {
+	ids := append([]string(nil), event.Participants...)
+	for _, g := range event.Guests {
+		ids = append(ids, g.ID)
+	}
+	return ids
+}
+
+// partitionParticipants splits an event's participants into those still
+// expected to attend and those who explicitly declined.
+func partitionParticipants(event *model.Event, availMap map[string]model.Availability) (active, declined []string) {
+	for _, userID := range allParticipants(event) {
+		if av, ok := availMap[userID]; ok && av.Declined {
+			declined = append(declined, userID)
+		} else {
+			active = append(active, userID)
+		}
+	}
+	return active, declined
+}
+
 func getMissingUsers2(all []string, present []string) []string {
 	set := make(map[string]struct{}, len(present))
 	for _, u := range present {
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"slices"
+	"sort"
+	"time"
+)
+
+const (
+	// maxSplitSuggestions bounds how many equally good session sets are returned.
+	maxSplitSuggestions = 10
+	splitSearchBudget   = 200000
+)
+
+// SuggestSplitSessions suggests sets of non-overlapping sessions that together
+// cover the event's duration, maximizing the number of participants free for
+// every session. Session counts from 1 up to the event's MaxSessions are tried;
+// fewer sessions win ties. Sessions need not be equally long: each is either
+// an equal share of the duration or MinSessionMin plus whole suggestion
+// steps, except the last, which takes what remains.
+func (s *SchedulerService) SuggestSplitSessions(ctx context.Context, eventID string) (*model.SplitSuggestionResult, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.SuggestSplitSessions", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	event, err := s.ensureEventExists(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.Split == nil {
+		return nil, fmt.Errorf("event %s is not configured for split sessions", eventID)
+	}
+	start := time.Now()
+	searched := 0
+	defer func() {
+		s.observer.ObserveSuggestion("split", len(allParticipants(event)), searched, time.Since(start))
+	}()
+
+	result := &model.SplitSuggestionResult{
+		Suggestions:        []model.SessionSuggestion{},
+		RequiredAttendance: requiredAttendance(event),
+	}
+
+	availMap, err := s.availabilityRepo.GetByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(availMap) == 0 {
+		result.Reason = "no participant has submitted availability yet"
+		return result, nil
+	}
+
+	active, _ := partitionParticipants(event, availMap)
+	duration := time.Duration(event.DurationMin) * time.Minute
+	minLength := time.Duration(event.Split.MinSessionMin) * time.Minute
+	scored := map[time.Duration][]scoredWindow{}
+	search := &splitSearch{ctx: ctx, budget: splitSearchBudget, minLength: minLength}
+	search.windowsOf = func(length time.Duration) ([]scoredWindow, error) {
+		if windows, ok := scored[length]; ok {
+			return windows, nil
+		}
+		windows, err := s.scoreWindowsOfLength(ctx, event, availMap, length)
+		if err != nil {
+			return nil, err
+		}
+		searched += len(windows)
+		sort.SliceStable(windows, func(i, j int) bool {
+			return windows[i].slot.Start.Before(windows[j].slot.Start)
+		})
+		scored[length] = windows
+		return windows, nil
+	}
+	for sessions := 1; sessions <= event.Split.MaxSessions; sessions++ {
+		longest := duration - time.Duration(sessions-1)*minLength
+		if longest < minLength {
+			break
+		}
+		// Lengths of all sessions but the last, which the search works out.
+		var lengths []time.Duration
+		if sessions > 1 && event.DurationMin%sessions == 0 {
+			lengths = append(lengths, duration/time.Duration(sessions))
+		}
+		for length := minLength; sessions > 1 && length <= longest; length += s.suggestionStep {
+			if !slices.Contains(lengths, length) {
+				lengths = append(lengths, length)
+			}
+		}
+		var windows []scoredWindow
+		for _, length := range lengths {
+			w, err := search.windowsOf(length)
+			if err != nil {
+				return nil, err
+			}
+			windows = append(windows, w...)
+		}
+		sort.SliceStable(windows, func(i, j int) bool {
+			return windows[i].slot.Start.Before(windows[j].slot.Start)
+		})
+		search.windows = windows
+		search.sessions = sessions
+		search.run(time.Time{}, duration, nil, nil)
+		if search.err != nil {
+			return nil, search.err
+		}
+	}
+
+	result.BestAttendance = search.bestCount
+	if search.bestCount < result.RequiredAttendance {
+		result.Reason = fmt.Sprintf("best session set has %d of %d participants available, quorum requires %d",
+			search.bestCount, len(allParticipants(event)), result.RequiredAttendance)
+		return result, nil
+	}
+
+	for _, candidate := range search.best {
+		attendees := make([]string, 0, len(candidate.common))
+		for userID := range candidate.common {
+			attendees = append(attendees, userID)
+		}
+		sort.Strings(attendees)
+		result.Suggestions = append(result.Suggestions, model.SessionSuggestion{
+			Sessions:         candidate.sessions,
+			Attendees:        attendees,
+			UnavailableUsers: getMissingUsers2(active, attendees),
+		})
+	}
+	result.Viable = true
+	return result, nil
+}
+
+type splitCandidate struct {
+	sessions []model.Slot
+	common   map[string]struct{}
+}
+
+// splitSearch picks `sessions` chronologically ordered, non-overlapping
+// windows adding up to the event's duration and keeps the sets with the
+// largest common attendance. All but the last session come from windows; the
+// last is a window of windowsOf whatever length remains.
+type splitSearch struct {
+	ctx       context.Context
+	err       error
+	windows   []scoredWindow
+	windowsOf func(length time.Duration) ([]scoredWindow, error)
+	minLength time.Duration
+	sessions  int
+	best      []splitCandidate
+	bestCount int
+	budget    int
+}
+
+func (p *splitSearch) run(prevEnd time.Time, remaining time.Duration, chosen []model.Slot, common map[string]struct{}) {
+	left := p.sessions - len(chosen)
+	if left == 0 {
+		p.record(chosen, common)
+		return
+	}
+	windows, longest := p.windows, remaining-time.Duration(left-1)*p.minLength
+	if left == 1 {
+		if windows, p.err = p.windowsOf(remaining); p.err != nil {
+			return
+		}
+	}
+	from := sort.Search(len(windows), func(i int) bool { return !windows[i].slot.Start.Before(prevEnd) })
+	for _, w := range windows[from:] {
+		if p.err != nil || p.budget <= 0 {
+			return
+		}
+		p.budget--
+		if p.budget%cancelCheckInterval == 0 {
+			if p.err = p.ctx.Err(); p.err != nil {
+				return
+			}
+		}
+
+		length := w.slot.End.Sub(w.slot.Start)
+		if length > longest {
+			continue
+		}
+		next := intersect(common, w.available, chosen == nil)
+		if len(next) == 0 || len(next) < p.bestCount {
+			continue
+		}
+		p.run(w.slot.End, remaining-length, append(chosen[:len(chosen):len(chosen)], w.slot), next)
+	}
+}
+
+func (p *splitSearch) record(chosen []model.Slot, common map[string]struct{}) {
+	switch {
+	case len(common) > p.bestCount:
+		p.bestCount = len(common)
+		p.best = nil
+	case len(common) < p.bestCount || len(p.best) >= maxSplitSuggestions:
+		return
+	}
+	p.best = append(p.best, splitCandidate{sessions: append([]model.Slot(nil), chosen...), common: common})
+}
+
+// intersect narrows common to the users in available. When first is set
+// common is ignored and the result is simply the available users.
+func intersect(common map[string]struct{}, available []string, first bool) map[string]struct{} {
+	next := make(map[string]struct{}, len(available))
+	for _, userID := range available {
+		if _, ok := common[userID]; first || ok {
+			next[userID] = struct{}{}
+		}
+	}
+	return next
+}
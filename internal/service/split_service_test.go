@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWorkshop(t *testing.T) *model.Event {
+	t.Helper()
+	return &model.Event{
+		ID: "workshop", DurationMin: 180, Participants: participants(2),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(17, 0)}},
+		Split: &model.SplitConfig{MaxSessions: 2, MinSessionMin: 60},
+	}
+}
+
+func TestSuggestSplitSessions(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", newWorkshop(t)))
+	free := []model.Slot{{Start: at(9, 0), End: at(10, 30)}, {Start: at(14, 0), End: at(15, 30)}}
+	for _, u := range participants(2) {
+		require.NoError(t, svc.AddAvailability(t.Context(), u, &model.Availability{EventID: "workshop", UserID: u, Slots: free}))
+	}
+
+	contiguous, err := svc.SuggestSlots(t.Context(), "workshop")
+	require.NoError(t, err)
+	assert.False(t, contiguous.Viable)
+
+	result, err := svc.SuggestSplitSessions(t.Context(), "workshop")
+	require.NoError(t, err)
+	assert.True(t, result.Viable)
+	assert.Equal(t, 2, result.BestAttendance)
+	require.Len(t, result.Suggestions, 1)
+	assert.Equal(t, free, result.Suggestions[0].Sessions)
+	assert.Equal(t, []string{"u1", "u2"}, result.Suggestions[0].Attendees)
+	assert.Empty(t, result.Suggestions[0].UnavailableUsers)
+}
+
+func TestSuggestSplitSessions_UnequalLengths(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "workshop", DurationMin: 100, Participants: participants(2),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(17, 0)}},
+		Split: &model.SplitConfig{MaxSessions: 3, MinSessionMin: 30},
+	}))
+	free := []model.Slot{{Start: at(9, 0), End: at(9, 30)}, {Start: at(11, 0), End: at(11, 30)}, {Start: at(14, 0), End: at(14, 40)}}
+	for _, u := range participants(2) {
+		require.NoError(t, svc.AddAvailability(t.Context(), u, &model.Availability{EventID: "workshop", UserID: u, Slots: free}))
+	}
+
+	result, err := svc.SuggestSplitSessions(t.Context(), "workshop")
+	require.NoError(t, err)
+	assert.True(t, result.Viable)
+	require.Len(t, result.Suggestions, 1)
+	assert.Equal(t, free, result.Suggestions[0].Sessions)
+
+	_, err = svc.FinalizeEvent(t.Context(), "u1", "workshop", result.Suggestions[0].Sessions)
+	assert.NoError(t, err)
+}
+
+func TestSuggestSplitSessions_NotConfigured(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", DurationMin: 60, Participants: participants(1)}))
+
+	_, err := svc.SuggestSplitSessions(t.Context(), "e1")
+	assert.EqualError(t, err, "event e1 is not configured for split sessions")
+}
+
+func TestFinalizeEvent_Sessions(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", newWorkshop(t)))
+
+	tests := []struct {
+		name     string
+		sessions []model.Slot
+		wantErr  string
+	}{
+		{"no sessions", nil, "event must be finalized with between 1 and 2 sessions"},
+		{"too short", []model.Slot{{Start: at(9, 0), End: at(9, 30)}, {Start: at(10, 0), End: at(12, 30)}}, "session 1 is shorter than 60 minutes"},
+		{"outside slots", []model.Slot{{Start: at(16, 0), End: at(19, 0)}}, "session 1 is outside the event's candidate slots"},
+		{"overlap", []model.Slot{{Start: at(9, 0), End: at(10, 30)}, {Start: at(10, 0), End: at(11, 30)}}, "sessions 1 and 2 overlap"},
+		{"wrong total", []model.Slot{{Start: at(9, 0), End: at(10, 0)}, {Start: at(11, 0), End: at(12, 0)}}, "sessions add up to 120 minutes, event needs 180"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.FinalizeEvent(t.Context(), "u1", "workshop", tt.sessions)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	sessions := []model.Slot{{Start: at(14, 0), End: at(15, 30)}, {Start: at(9, 0), End: at(10, 30)}}
+	event, err := svc.FinalizeEvent(t.Context(), "u1", "workshop", sessions)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Slot{sessions[1], sessions[0]}, event.FinalSessions)
+
+	stored, err := svc.GetEvent(t.Context(), "workshop")
+	require.NoError(t, err)
+	assert.Equal(t, event.FinalSessions, stored.FinalSessions)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"meeting-scheduler/internal/model"
+)
+
+// CountRecords counts the stored users, live events and the availability
+// entries of those events.
+func (s *SchedulerService) CountRecords(ctx context.Context) (*model.RecordCounts, error) {
+	users, err := s.userRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.eventRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := &model.RecordCounts{Users: len(users), Events: len(events)}
+	for _, e := range events {
+		availability, err := s.availabilityRepo.GetByEvent(ctx, e.ID)
+		if err != nil {
+			return nil, err
+		}
+		counts.Availability += len(availability)
+	}
+	return counts, nil
+}
@@ -1,53 +1,157 @@
 package service
 
 import (
+	"context"
+	"fmt"
 	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
 	"time"
 )
 
-func (s *SchedulerService) SuggestSlots(eventID string) ([]model.SlotSuggestion, error) {
-	event, err := s.ensureEventExists(eventID)
+// defaultSuggestionStep is how far apart the start times of candidate
+// windows are unless WithSuggestionStep says otherwise.
+const defaultSuggestionStep = 15 * time.Minute
+
+// cancelCheckInterval is how many nodes the batch and split searches visit
+// between checks for a cancelled context.
+const cancelCheckInterval = 1024
+
+// scoredWindow is a candidate meeting window together with the users whose
+// availability fully covers it.
+type scoredWindow struct {
+	slot      model.Slot
+	available []string
+}
+
+func (s *SchedulerService) SuggestSlots(ctx context.Context, eventID string) (*model.SuggestionResult, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.SuggestSlots", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	event, err := s.ensureEventExists(ctx, eventID)
 	if err != nil {
 		return nil, err
 	}
+	start := time.Now()
+	var windows []scoredWindow
+	defer func() {
+		s.observer.ObserveSuggestion("slots", len(allParticipants(event)), len(windows), time.Since(start))
+	}()
 
-	availMap := s.availabilityRepo.GetByEvent(eventID)
+	result := &model.SuggestionResult{
+		SuggestedSlots:     []model.SlotSuggestion{},
+		RequiredAttendance: requiredAttendance(event),
+	}
+
+	availMap, err := s.availabilityRepo.GetByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
 	if len(availMap) == 0 {
-		return nil, nil
+		result.Reason = "no participant has submitted availability yet"
+		return result, nil
 	}
 
-	required := time.Duration(event.DurationMin) * time.Minute
-	step := 15 * time.Minute
+	windows, err = s.scoreWindows(ctx, event, availMap)
+	if err != nil {
+		return nil, err
+	}
+	active, declined := partitionParticipants(event, availMap)
 
 	var best []model.SlotSuggestion
 	bestCount := 0
 
-	for _, slot := range event.Slots {
-		for start := slot.Start; start.Add(required).Before(slot.End) || start.Add(required).Equal(slot.End); start = start.Add(step) {
-			end := start.Add(required)
-			window := model.Slot{Start: start, End: end}
+	for _, w := range windows {
+		if len(w.available) > bestCount {
+			bestCount = len(w.available)
+			best = []model.SlotSuggestion{{
+				Slot:             w.slot,
+				UnavailableUsers: getMissingUsers2(active, w.available),
+				DeclinedUsers:    declined,
+			}}
+		} else if len(w.available) == bestCount && bestCount > 0 {
+			best = append(best, model.SlotSuggestion{
+				Slot:             w.slot,
+				UnavailableUsers: getMissingUsers2(active, w.available),
+				DeclinedUsers:    declined,
+			})
+		}
+	}
 
-			var available []string
-			for userID, av := range availMap {
-				if isUserAvailableForExactWindow(window, av.Slots) {
-					available = append(available, userID)
-				}
-			}
+	result.BestAttendance = bestCount
+	switch {
+	case len(windows) == 0:
+		result.Reason = fmt.Sprintf("no candidate slot is long enough for a %d minute event", event.DurationMin)
+	case bestCount < result.RequiredAttendance:
+		result.Reason = fmt.Sprintf("best window has %d of %d participants available, quorum requires %d",
+			bestCount, len(allParticipants(event)), result.RequiredAttendance)
+	default:
+		result.Viable = true
+		result.SuggestedSlots = best
+	}
+	return result, nil
+}
+
+// candidateWindows lists every window of the given length that starts on the
+// suggestion step grid within one of the candidate slots.
+func candidateWindows(slots []model.Slot, length, step time.Duration) []model.Slot {
+	var windows []model.Slot
+	for _, slot := range slots {
+		for start := slot.Start; start.Add(length).Before(slot.End) || start.Add(length).Equal(slot.End); start = start.Add(step) {
+			windows = append(windows, model.Slot{Start: start, End: start.Add(length)})
+		}
+	}
+	return windows
+}
+
+func (s *SchedulerService) scoreWindows(ctx context.Context, event *model.Event, availMap map[string]model.Availability) ([]scoredWindow, error) {
+	return s.scoreWindowsOfLength(ctx, event, availMap, time.Duration(event.DurationMin)*time.Minute)
+}
 
-			if len(available) > bestCount {
-				bestCount = len(available)
-				best = []model.SlotSuggestion{{
-					Slot:             window,
-					UnavailableUsers: getMissingUsers2(event.Participants, available),
-				}}
-			} else if len(available) == bestCount && bestCount > 0 {
-				best = append(best, model.SlotSuggestion{
-					Slot:             window,
-					UnavailableUsers: getMissingUsers2(event.Participants, available),
-				})
+// scoreWindowsOfLength stops with ctx's error once ctx is done, as events
+// with wide slots and many participants can take a while to score. Only the
+// event's participants and guests are counted, and those who declined never
+// count as available, whatever slots they have on record.
+func (s *SchedulerService) scoreWindowsOfLength(ctx context.Context, event *model.Event, availMap map[string]model.Availability, length time.Duration) ([]scoredWindow, error) {
+	windows := candidateWindows(event.Slots, length, s.suggestionStep)
+	participants := allParticipants(event)
+	_, span := tracing.Start(ctx, "scoreWindows",
+		tracing.AttrEventID.String(event.ID),
+		tracing.AttrParticipants.Int(len(participants)),
+		tracing.AttrWindows.Int(len(windows)),
+	)
+	defer span.End()
+	scored := make([]scoredWindow, 0, len(windows))
+	for _, window := range windows {
+		if err := ctx.Err(); err != nil {
+			tracing.RecordError(span, err)
+			return nil, err
+		}
+		var available []string
+		for _, userID := range participants {
+			if av, ok := availMap[userID]; ok && !av.Declined && isUserAvailableForExactWindow(window, av.Slots) {
+				available = append(available, userID)
 			}
 		}
+		scored = append(scored, scoredWindow{slot: window, available: available})
 	}
+	return scored, nil
+}
 
-	return best, nil
+// requiredAttendance resolves an event's quorum into a head count. Events
+// without a quorum only need one available participant.
+func requiredAttendance(event *model.Event) int {
+	required := 1
+	if event.Quorum == nil {
+		return required
+	}
+	if event.Quorum.MinCount > required {
+		required = event.Quorum.MinCount
+	}
+	if event.Quorum.MinPercent > 0 {
+		// round up so that 50% of 5 participants needs 3, not 2
+		byPercent := (event.Quorum.MinPercent*len(allParticipants(event)) + 99) / 100
+		if byPercent > required {
+			required = byPercent
+		}
+	}
+	return required
 }
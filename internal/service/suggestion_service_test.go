@@ -0,0 +1,223 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var day = time.Date(2025, time.May, 20, 0, 0, 0, 0, time.UTC)
+
+func at(hour, min int) time.Time {
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute)
+}
+
+// newInMemoryService wires a SchedulerService over in-memory repositories
+// and registers users u1..uN.
+func newInMemoryService(t *testing.T, users int, opts ...service.Option) *service.SchedulerService {
+	t.Helper()
+	svc := service.NewSchedulerService(
+		repository.NewInMemoryUserRepository(),
+		repository.NewInMemoryEventRepository(),
+		repository.NewInMemoryAvailabilityRepository(),
+		opts...,
+	)
+	for i := 1; i <= users; i++ {
+		id := fmt.Sprintf("u%d", i)
+		require.NoError(t, svc.CreateUser(t.Context(), &model.User{ID: id, Name: id}))
+	}
+	return svc
+}
+
+func participants(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("u%d", i+1)
+	}
+	return ids
+}
+
+func TestSuggestSlots_NoResponses(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(2),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+
+	result, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.False(t, result.Viable)
+	assert.NotNil(t, result.SuggestedSlots)
+	assert.Empty(t, result.SuggestedSlots)
+	assert.Contains(t, result.Reason, "no participant has submitted availability")
+}
+
+func TestSuggestSlots_QuorumNotMet(t *testing.T) {
+	svc := newInMemoryService(t, 8)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(8),
+		Slots:  []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+		Quorum: &model.Quorum{MinCount: 5},
+	}))
+	for _, u := range []string{"u1", "u2"} {
+		require.NoError(t, svc.AddAvailability(t.Context(), u, &model.Availability{
+			EventID: "e1", UserID: u, Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+		}))
+	}
+
+	result, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.False(t, result.Viable)
+	assert.Empty(t, result.SuggestedSlots)
+	assert.Equal(t, 5, result.RequiredAttendance)
+	assert.Equal(t, 2, result.BestAttendance)
+	assert.Contains(t, result.Reason, "best window has 2 of 8")
+}
+
+func TestSuggestSlots_QuorumPercentMet(t *testing.T) {
+	svc := newInMemoryService(t, 4)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(4),
+		Slots:  []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+		Quorum: &model.Quorum{MinPercent: 50},
+	}))
+	for _, u := range []string{"u1", "u2"} {
+		require.NoError(t, svc.AddAvailability(t.Context(), u, &model.Availability{
+			EventID: "e1", UserID: u, Slots: []model.Slot{{Start: at(10, 0), End: at(11, 0)}},
+		}))
+	}
+
+	result, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.True(t, result.Viable)
+	assert.Equal(t, 2, result.RequiredAttendance)
+	require.Len(t, result.SuggestedSlots, 1)
+	assert.Equal(t, at(10, 0), result.SuggestedSlots[0].Slot.Start)
+	assert.ElementsMatch(t, []string{"u3", "u4"}, result.SuggestedSlots[0].UnavailableUsers)
+}
+
+func TestSuggestSlots_NoWindowFitsDuration(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 120, Participants: participants(1),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{
+		EventID: "e1", UserID: "u1", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}},
+	}))
+
+	result, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.False(t, result.Viable)
+	assert.Contains(t, result.Reason, "no candidate slot is long enough")
+}
+
+func TestSuggestSlots_EventNotFound(t *testing.T) {
+	svc := newInMemoryService(t, 0)
+
+	result, err := svc.SuggestSlots(t.Context(), "missing")
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestCreateEvent_InvalidQuorum(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+
+	err := svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 30, Participants: participants(2),
+		Quorum: &model.Quorum{MinCount: 3},
+	})
+	assert.EqualError(t, err, "quorum min_count must be between 0 and 2")
+
+	err = svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 30, Participants: participants(2),
+		Quorum: &model.Quorum{MinPercent: 150},
+	})
+	assert.EqualError(t, err, "quorum min_percent must be between 0 and 100")
+}
+
+func TestSuggestSlots_Cancelled(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(1),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{
+		EventID: "e1", UserID: "u1", Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	_, err := svc.SuggestSlots(ctx, "e1")
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = svc.ScheduleBatch(ctx, []string{"e1"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSuggestSlots_SuggestionStep(t *testing.T) {
+	svc := newInMemoryService(t, 1, service.WithSuggestionStep(time.Hour))
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(1),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{
+		EventID: "e1", UserID: "u1", Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+
+	result, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	var starts []time.Time
+	for _, s := range result.SuggestedSlots {
+		starts = append(starts, s.Slot.Start)
+	}
+	assert.Equal(t, []time.Time{at(9, 0), at(10, 0), at(11, 0)}, starts)
+}
+
+func TestSuggestSlots_IgnoresNonPositiveStep(t *testing.T) {
+	for _, step := range []time.Duration{0, -time.Minute} {
+		svc := newInMemoryService(t, 1, service.WithSuggestionStep(step))
+		require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+			ID: "e1", DurationMin: 60, Participants: participants(1),
+			Slots: []model.Slot{{Start: at(9, 0), End: at(10, 30)}},
+		}))
+		require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{
+			EventID: "e1", UserID: "u1", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 30)}},
+		}))
+
+		result, err := svc.SuggestSlots(t.Context(), "e1")
+		require.NoError(t, err)
+		assert.Len(t, result.SuggestedSlots, 3, "step %s falls back to 15 minutes", step)
+	}
+}
+
+func TestSuggestSlots_IgnoresAvailabilityOfNonParticipants(t *testing.T) {
+	availability := repository.NewInMemoryAvailabilityRepository()
+	svc := service.NewSchedulerService(repository.NewInMemoryUserRepository(), repository.NewInMemoryEventRepository(), availability)
+	for _, id := range participants(3) {
+		require.NoError(t, svc.CreateUser(t.Context(), &model.User{ID: id, Name: id}))
+	}
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", DurationMin: 60, Participants: participants(2),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(11, 0)}},
+	}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{
+		EventID: "e1", Slots: []model.Slot{{Start: at(9, 0), End: at(11, 0)}},
+	}))
+	// A row stored before writes were limited to participants.
+	require.NoError(t, availability.Create(t.Context(), model.Availability{
+		EventID: "e1", UserID: "u3", Slots: []model.Slot{{Start: at(10, 0), End: at(11, 0)}},
+	}))
+
+	result, err := svc.SuggestSlots(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.BestAttendance)
+	require.Len(t, result.SuggestedSlots, 5, "u3 does not make 10:00 the only best window")
+	assert.Equal(t, []string{"u2"}, result.SuggestedSlots[4].UnavailableUsers)
+}
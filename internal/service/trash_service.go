@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/tracing"
+	"sort"
+	"time"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	// systemActor is recorded as the actor of changes the server makes on
+	// its own, such as purging the trash.
+	systemActor = "system"
+)
+
+// ListTrash returns the trashed events the actor organizes, most recently
+// deleted first.
+func (s *SchedulerService) ListTrash(ctx context.Context, actorID string) ([]*model.Event, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.ListTrash")
+	defer span.End()
+	trashed, err := s.eventRepo.ListTrashed(ctx)
+	if err != nil {
+		return nil, err
+	}
+	events := []*model.Event{}
+	for _, e := range trashed {
+		if authorizeOrganizer(e, actorID) == nil {
+			events = append(events, e)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].DeletedAt.After(*events[j].DeletedAt)
+	})
+	return events, nil
+}
+
+// UndeleteEvent takes an event back out of the trash. Only its organizers
+// may do so.
+func (s *SchedulerService) UndeleteEvent(ctx context.Context, actorID, id string) (*model.Event, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.UndeleteEvent", tracing.AttrEventID.String(id))
+	defer span.End()
+	var restored *model.Event
+	err := s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		trashed, err := tx.eventRepo.GetTrashed(ctx, id)
+		if err != nil || trashed == nil {
+			return fmt.Errorf("event with ID %s is not in the trash", id)
+		}
+		if err := authorizeOrganizer(trashed, actorID); err != nil {
+			return err
+		}
+		if err := tx.eventRepo.Restore(ctx, id); err != nil {
+			return err
+		}
+		if restored, err = tx.eventRepo.Get(ctx, id); err != nil {
+			return err
+		}
+		return tx.record(ctx, actorID, model.AuditRestore, model.EntityEvent, id, id, nil, restored)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// PurgeTrash permanently removes events that have been in the trash longer
+// than the retention period, together with their availability, version
+// history and magic links. It returns the IDs of the purged events.
+func (s *SchedulerService) PurgeTrash(ctx context.Context) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.PurgeTrash")
+	defer span.End()
+	cutoff := s.now().Add(-s.trashRetention)
+	purged := []string{}
+	trashed, err := s.eventRepo.ListTrashed(ctx)
+	if err != nil {
+		return purged, err
+	}
+	for _, e := range trashed {
+		if e.DeletedAt.After(cutoff) {
+			continue
+		}
+		gone := false
+		err := s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+			// The event may have been restored since the trash was listed.
+			current, err := tx.eventRepo.GetTrashed(ctx, e.ID)
+			if err != nil || current == nil || current.DeletedAt.After(cutoff) {
+				return nil
+			}
+			if err := repository.PurgeEvent(ctx, tx.eventRepo, tx.availabilityRepo, tx.versionRepo, e.ID); err != nil {
+				return err
+			}
+			gone = true
+			return tx.appendAudit(ctx, systemActor, model.AuditPurge, model.EntityEvent, e.ID, e.ID, current, nil)
+		})
+		if err != nil {
+			return purged, err
+		}
+		if !gone {
+			continue
+		}
+		// Magic links cannot be rolled back, so they go only once the event
+		// is gone for good.
+		if err := s.linkRepo.DeleteByEvent(ctx, e.ID); err != nil {
+			return purged, err
+		}
+		purged = append(purged, e.ID)
+	}
+	sort.Strings(purged)
+	return purged, nil
+}
+
+// RunTrashPurger calls PurgeTrash every interval until ctx is cancelled.
+func (s *SchedulerService) RunTrashPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeTrash(ctx)
+			if err != nil {
+				s.logger.ErrorContext(ctx, "purging trash", "error", err)
+			}
+			if len(purged) > 0 {
+				s.logger.InfoContext(ctx, "purged events from the trash", "event_ids", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteEvent_MovesToTrash(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", DurationMin: 30, Participants: participants(2)}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u2", &model.Availability{EventID: "e1", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}}}))
+
+	require.NoError(t, svc.DeleteEvent(t.Context(), "u1", "e1"))
+	_, err := svc.GetEvent(t.Context(), "e1")
+	assert.Error(t, err)
+	err = svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", DurationMin: 30, Participants: participants(1)})
+	assert.EqualError(t, err, "event with ID e1 is in the trash")
+
+	trash, err := svc.ListTrash(t.Context(), "u2")
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+	trash, err = svc.ListTrash(t.Context(), "u1")
+	require.NoError(t, err)
+	require.Len(t, trash, 1)
+	assert.NotNil(t, trash[0].DeletedAt)
+
+	_, err = svc.UndeleteEvent(t.Context(), "u2", "e1")
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	event, err := svc.UndeleteEvent(t.Context(), "u1", "e1")
+	require.NoError(t, err)
+	assert.Nil(t, event.DeletedAt)
+	av, err := svc.GetAvailability(t.Context(), "e1", "u2")
+	require.NoError(t, err)
+	assert.Len(t, av.Slots, 1, "availability survives the round trip through the trash")
+
+	_, err = svc.UndeleteEvent(t.Context(), "u1", "e1")
+	assert.EqualError(t, err, "event with ID e1 is not in the trash")
+}
+
+func TestPurgeTrash_AfterRetention(t *testing.T) {
+	now := at(9, 0)
+	events := repository.NewInMemoryEventRepository()
+	availability := repository.NewInMemoryAvailabilityRepository()
+	links := repository.NewInMemoryMagicLinkRepository()
+	svc := service.NewSchedulerService(repository.NewInMemoryUserRepository(), events, availability,
+		service.WithMagicLinks(links),
+		service.WithTrashRetention(24*time.Hour),
+		service.WithClock(func() time.Time { return now }),
+	)
+	require.NoError(t, svc.CreateUser(t.Context(), &model.User{ID: "u1"}))
+	for _, id := range []string{"old", "recent"} {
+		require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: id, DurationMin: 30, Participants: []string{"u1"}}))
+		require.NoError(t, svc.AddAvailability(t.Context(), "u1", &model.Availability{EventID: id}))
+		_, err := svc.CreateMagicLink(t.Context(), "u1", id, model.MagicLinkRequest{Name: "Visitor"})
+		require.NoError(t, err)
+	}
+	require.NoError(t, svc.DeleteEvent(t.Context(), "u1", "old"))
+	now = now.Add(20 * time.Hour)
+	require.NoError(t, svc.DeleteEvent(t.Context(), "u1", "recent"))
+	now = now.Add(5 * time.Hour)
+
+	purged, err := svc.PurgeTrash(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old"}, purged)
+
+	_, err = events.GetTrashed(t.Context(), "old")
+	assert.Error(t, err)
+	entries, err := availability.GetByEvent(t.Context(), "old")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	remaining, err := links.ListByEvent(t.Context(), "old")
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+	_, err = svc.ListEventVersions(t.Context(), "old")
+	assert.Error(t, err)
+	_, err = events.GetTrashed(t.Context(), "recent")
+	assert.NoError(t, err)
+	entries, err = availability.GetByEvent(t.Context(), "recent")
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	remaining, err = links.ListByEvent(t.Context(), "recent")
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1)
+
+	history, err := svc.GetEventHistory(t.Context(), "old")
+	require.NoError(t, err)
+	last := history[len(history)-1]
+	assert.Equal(t, model.AuditPurge, last.Action)
+	assert.Equal(t, "system", last.Actor)
+}
@@ -1,20 +1,27 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"strings"
 )
 
-func (s *SchedulerService) GetUser(id string) (*model.User, error) {
-	user, err := s.userRepo.Get(id)
+func (s *SchedulerService) GetUser(ctx context.Context, id string) (*model.User, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.GetUser")
+	defer span.End()
+	user, err := s.userRepo.Get(ctx, id)
 	if err != nil || user == nil {
 		return nil, fmt.Errorf("user with ID %s not found", id)
 	}
 	return user, nil
 }
 
-func (s *SchedulerService) GetAllUsers() ([]*model.User, error) {
-	userMap, err := s.userRepo.GetAll()
+func (s *SchedulerService) GetAllUsers(ctx context.Context) ([]*model.User, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.GetAllUsers")
+	defer span.End()
+	userMap, err := s.userRepo.GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -25,9 +32,26 @@ func (s *SchedulerService) GetAllUsers() ([]*model.User, error) {
 	return users, nil
 }
 
-func (s *SchedulerService) CreateUser(u *model.User) error {
-	if existing, _ := s.userRepo.Get(u.ID); existing != nil {
+func (s *SchedulerService) CreateUser(ctx context.Context, u *model.User) error {
+	ctx, span := tracing.Start(ctx, "SchedulerService.CreateUser")
+	defer span.End()
+	return s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		return tx.createUser(ctx, u)
+	})
+}
+
+// createUser stores u unless its ID is taken. It must run inside atomically,
+// so that no other sign-up takes the ID between the check and the write.
+func (s *SchedulerService) createUser(ctx context.Context, u *model.User) error {
+	if strings.HasPrefix(u.ID, guestIDPrefix) {
+		return fmt.Errorf("user IDs may not start with %q", guestIDPrefix)
+	}
+	if existing, _ := s.userRepo.Get(ctx, u.ID); existing != nil {
 		return fmt.Errorf("user with ID %s already exists", u.ID)
 	}
-	return s.userRepo.Create(u)
+	if err := s.userRepo.Create(ctx, u); err != nil {
+		return err
+	}
+	// Users sign themselves up, so the new user is the actor.
+	return s.record(ctx, u.ID, model.AuditCreate, model.EntityUser, u.ID, "", nil, u)
 }
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"meeting-scheduler/internal/model"
 	"meeting-scheduler/internal/service"
@@ -15,7 +16,7 @@ type MockUserRepo struct {
 	mock.Mock
 }
 
-func (m *MockUserRepo) Get(id string) (*model.User, error) {
+func (m *MockUserRepo) Get(_ context.Context, id string) (*model.User, error) {
 	args := m.Called(id)
 	user := args.Get(0)
 	if user == nil {
@@ -24,16 +25,21 @@ func (m *MockUserRepo) Get(id string) (*model.User, error) {
 	return user.(*model.User), args.Error(1)
 }
 
-func (m *MockUserRepo) GetAll() (map[string]*model.User, error) {
+func (m *MockUserRepo) GetAll(_ context.Context) (map[string]*model.User, error) {
 	args := m.Called()
 	return args.Get(0).(map[string]*model.User), args.Error(1)
 }
 
-func (m *MockUserRepo) Create(user *model.User) error {
+func (m *MockUserRepo) Create(_ context.Context, user *model.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
+func (m *MockUserRepo) Delete(_ context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func setup() (*service.SchedulerService, *MockUserRepo) {
 	mockRepo := new(MockUserRepo)
 	// Use a constructor or exported fields to set dependencies
@@ -47,7 +53,7 @@ func TestGetUser_Success(t *testing.T) {
 
 	mockRepo.On("Get", "123").Return(user, nil)
 
-	result, err := svc.GetUser("123")
+	result, err := svc.GetUser(t.Context(), "123")
 	assert.NoError(t, err)
 	assert.Equal(t, user, result)
 
@@ -59,7 +65,7 @@ func TestGetUser_NotFound(t *testing.T) {
 
 	mockRepo.On("Get", "999").Return(nil, errors.New("not found"))
 
-	result, err := svc.GetUser("999")
+	result, err := svc.GetUser(t.Context(), "999")
 	assert.Error(t, err)
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "user with ID 999 not found")
@@ -76,7 +82,7 @@ func TestGetAllUsers_Success(t *testing.T) {
 
 	mockRepo.On("GetAll").Return(users, nil)
 
-	result, err := svc.GetAllUsers()
+	result, err := svc.GetAllUsers(t.Context())
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
 
@@ -90,7 +96,7 @@ func TestCreateUser_Success(t *testing.T) {
 	mockRepo.On("Get", "100").Return(nil, nil)
 	mockRepo.On("Create", newUser).Return(nil)
 
-	err := svc.CreateUser(newUser)
+	err := svc.CreateUser(t.Context(), newUser)
 	assert.NoError(t, err)
 
 	mockRepo.AssertExpectations(t)
@@ -102,7 +108,7 @@ func TestCreateUser_AlreadyExists(t *testing.T) {
 
 	mockRepo.On("Get", "1").Return(existingUser, nil)
 
-	err := svc.CreateUser(existingUser)
+	err := svc.CreateUser(t.Context(), existingUser)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "user with ID 1 already exists")
 
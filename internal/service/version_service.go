@@ -0,0 +1,251 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/tracing"
+	"sort"
+)
+
+// ListEventVersions returns every recorded version of the event, oldest first.
+func (s *SchedulerService) ListEventVersions(ctx context.Context, eventID string) ([]model.EventVersion, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.ListEventVersions", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	versions, err := s.versionRepo.List(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("event with ID %s has no recorded versions", eventID)
+	}
+	return versions, nil
+}
+
+func (s *SchedulerService) GetEventVersion(ctx context.Context, eventID string, version int) (*model.EventVersion, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.GetEventVersion", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	return s.versionRepo.Get(ctx, eventID, version)
+}
+
+// DiffEventVersions reports the event fields and availability entries that
+// differ between two versions.
+func (s *SchedulerService) DiffEventVersions(ctx context.Context, eventID string, from, to int) (*model.VersionDiff, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.DiffEventVersions", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	a, err := s.versionRepo.Get(ctx, eventID, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.versionRepo.Get(ctx, eventID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := marshalState(eventState(a.Event))
+	if err != nil {
+		return nil, err
+	}
+	after, err := marshalState(eventState(b.Event))
+	if err != nil {
+		return nil, err
+	}
+	eventChanges, err := diffFields(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &model.VersionDiff{
+		EventID:             eventID,
+		From:                from,
+		To:                  to,
+		EventChanges:        eventChanges,
+		AvailabilityChanges: []model.AvailabilityChange{},
+	}
+	if diff.EventChanges == nil {
+		diff.EventChanges = []model.FieldChange{}
+	}
+
+	old, cur := availabilityByUser(a.Availability), availabilityByUser(b.Availability)
+	users := make([]string, 0, len(old)+len(cur))
+	for userID := range old {
+		users = append(users, userID)
+	}
+	for userID := range cur {
+		if _, ok := old[userID]; !ok {
+			users = append(users, userID)
+		}
+	}
+	sort.Strings(users)
+
+	for _, userID := range users {
+		prev, hadPrev := old[userID]
+		next, hasNext := cur[userID]
+		change := model.AvailabilityChange{UserID: userID}
+		switch {
+		case !hadPrev:
+			change.Change, change.After = model.ChangeAdded, &next
+		case !hasNext:
+			change.Change, change.Before = model.ChangeRemoved, &prev
+		case !sameAvailability(prev, next):
+			change.Change, change.Before, change.After = model.ChangeChanged, &prev, &next
+		default:
+			continue
+		}
+		diff.AvailabilityChanges = append(diff.AvailabilityChanges, change)
+	}
+	return diff, nil
+}
+
+// RestoreEventVersion brings the event and its availability set back to the
+// state recorded in version. The guest list is kept as it is, as in
+// UpdateEvent, so magic links issued since stay valid. The restore is itself
+// recorded as a new version, so it can be undone the same way. Only
+// organizers may restore.
+func (s *SchedulerService) RestoreEventVersion(ctx context.Context, actorID, eventID string, version int) (*model.EventVersion, error) {
+	ctx, span := tracing.Start(ctx, "SchedulerService.RestoreEventVersion", tracing.AttrEventID.String(eventID))
+	defer span.End()
+	err := s.atomically(ctx, func(ctx context.Context, tx *SchedulerService) error {
+		target, err := tx.versionRepo.Get(ctx, eventID, version)
+		if err != nil {
+			return err
+		}
+		if target.Event == nil {
+			return fmt.Errorf("version %d records the deletion of event %s and cannot be restored", version, eventID)
+		}
+		current, _ := tx.eventRepo.Get(ctx, eventID)
+		if current == nil {
+			if trashed, _ := tx.eventRepo.GetTrashed(ctx, eventID); trashed != nil {
+				return fmt.Errorf("event %s is in the trash and must be restored first", eventID)
+			}
+			return errEventNotFound(eventID)
+		}
+		if err := authorizeOrganizer(current, actorID); err != nil {
+			return err
+		}
+
+		restored := *target.Event
+		restored.Guests = current.Guests
+		if err := tx.eventRepo.Update(ctx, &restored); err != nil {
+			return err
+		}
+		if err := tx.appendAudit(ctx, actorID, model.AuditUpdate, model.EntityEvent, eventID, eventID, current, &restored); err != nil {
+			return err
+		}
+		if err := tx.restoreAvailability(ctx, actorID, eventID, target.Availability); err != nil {
+			return err
+		}
+		return tx.snapshotEvent(ctx, actorID, eventID, fmt.Sprintf("restore version %d", version))
+	})
+	if err != nil {
+		return nil, err
+	}
+	versions, err := s.versionRepo.List(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &versions[len(versions)-1], nil
+}
+
+// restoreAvailability replaces the event's availability set with wanted,
+// touching only the entries that differ.
+func (s *SchedulerService) restoreAvailability(ctx context.Context, actorID, eventID string, wanted []model.Availability) error {
+	current, err := s.availabilityRepo.GetByEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	keep := availabilityByUser(wanted)
+
+	stale := make([]string, 0, len(current))
+	for userID := range current {
+		if _, ok := keep[userID]; !ok {
+			stale = append(stale, userID)
+		}
+	}
+	sort.Strings(stale)
+	for _, userID := range stale {
+		before := current[userID]
+		if err := s.availabilityRepo.Delete(ctx, eventID, userID); err != nil {
+			return err
+		}
+		if err := s.appendAudit(ctx, actorID, model.AuditDelete, model.EntityAvailability, availabilityEntityID(&before), eventID, &before, nil); err != nil {
+			return err
+		}
+	}
+
+	for _, av := range wanted {
+		before, exists := current[av.UserID]
+		switch {
+		case !exists:
+			if err := s.availabilityRepo.Create(ctx, av); err != nil {
+				return err
+			}
+			if err := s.appendAudit(ctx, actorID, model.AuditCreate, model.EntityAvailability, availabilityEntityID(&av), eventID, nil, &av); err != nil {
+				return err
+			}
+		case !sameAvailability(before, av):
+			if err := s.availabilityRepo.Update(ctx, av); err != nil {
+				return err
+			}
+			if err := s.appendAudit(ctx, actorID, model.AuditUpdate, model.EntityAvailability, availabilityEntityID(&av), eventID, &before, &av); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// snapshotEvent stores the event's current state and availability set as
+// its next version.
+func (s *SchedulerService) snapshotEvent(ctx context.Context, actorID, eventID, reason string) error {
+	v := model.EventVersion{
+		EventID:      eventID,
+		CreatedAt:    s.now(),
+		Actor:        actorID,
+		Reason:       reason,
+		Availability: []model.Availability{},
+	}
+	if event, err := s.eventRepo.Get(ctx, eventID); err == nil && event != nil {
+		snapshot := *event
+		v.Event = &snapshot
+	}
+	availability, err := s.availabilityRepo.GetByEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	for _, av := range availability {
+		v.Availability = append(v.Availability, av)
+	}
+	sort.Slice(v.Availability, func(i, j int) bool {
+		return v.Availability[i].UserID < v.Availability[j].UserID
+	})
+	if err := s.versionRepo.Append(ctx, &v); err != nil {
+		return fmt.Errorf("recording event version: %w", err)
+	}
+	return nil
+}
+
+// eventState keeps a missing event a nil interface so it marshals to nothing.
+func eventState(e *model.Event) any {
+	if e == nil {
+		return nil
+	}
+	return e
+}
+
+func availabilityByUser(avs []model.Availability) map[string]model.Availability {
+	m := make(map[string]model.Availability, len(avs))
+	for _, av := range avs {
+		m[av.UserID] = av
+	}
+	return m
+}
+
+// sameAvailability compares the serialized form of two entries.
+func sameAvailability(a, b model.Availability) bool {
+	x, errX := json.Marshal(a)
+	y, errY := json.Marshal(b)
+	return errX == nil && errY == nil && bytes.Equal(x, y)
+}
@@ -0,0 +1,173 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventVersions_RecordEachChange(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", Title: "Draft", DurationMin: 30, Participants: participants(2)}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u2", &model.Availability{EventID: "e1", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}}}))
+	require.NoError(t, svc.UpdateEvent(t.Context(), "u1", &model.Event{ID: "e1", Title: "Final", DurationMin: 30, Participants: participants(2)}))
+
+	versions, err := svc.ListEventVersions(t.Context(), "e1")
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{versions[0].Version, versions[1].Version, versions[2].Version})
+	assert.Equal(t, "create event", versions[0].Reason)
+	assert.Empty(t, versions[0].Availability)
+	assert.Equal(t, "u2", versions[1].Actor)
+	require.Len(t, versions[1].Availability, 1)
+	assert.Equal(t, "Final", versions[2].Event.Title)
+
+	_, err = svc.ListEventVersions(t.Context(), "missing")
+	assert.Error(t, err)
+}
+
+func TestDiffEventVersions(t *testing.T) {
+	svc := newInMemoryService(t, 2)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", Title: "Draft", DurationMin: 30, Participants: participants(2)}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u2", &model.Availability{EventID: "e1", Slots: []model.Slot{{Start: at(9, 0), End: at(10, 0)}}}))
+	require.NoError(t, svc.UpdateEvent(t.Context(), "u1", &model.Event{ID: "e1", Title: "Final", DurationMin: 30, Participants: participants(2)}))
+
+	diff, err := svc.DiffEventVersions(t.Context(), "e1", 1, 3)
+	require.NoError(t, err)
+	require.Len(t, diff.EventChanges, 1)
+	assert.Equal(t, "title", diff.EventChanges[0].Field)
+	require.Len(t, diff.AvailabilityChanges, 1)
+	assert.Equal(t, "u2", diff.AvailabilityChanges[0].UserID)
+	assert.Equal(t, model.ChangeAdded, diff.AvailabilityChanges[0].Change)
+
+	_, err = svc.DiffEventVersions(t.Context(), "e1", 1, 9)
+	assert.Error(t, err)
+}
+
+func TestRestoreEventVersion(t *testing.T) {
+	svc := newInMemoryService(t, 3)
+	slots := []model.Slot{{Start: at(9, 0), End: at(10, 0)}}
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", Title: "Draft", DurationMin: 30, Participants: participants(3)}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u2", &model.Availability{EventID: "e1", Slots: slots}))
+	// Version 2 is the state to go back to.
+	require.NoError(t, svc.UpdateEvent(t.Context(), "u1", &model.Event{ID: "e1", Title: "Final", DurationMin: 30, Participants: participants(3)}))
+	require.NoError(t, svc.DeleteAvailability(t.Context(), "u2", "e1", "u2"))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u3", &model.Availability{EventID: "e1", Slots: slots}))
+
+	_, err := svc.RestoreEventVersion(t.Context(), "u2", "e1", 2)
+	assert.ErrorIs(t, err, service.ErrForbidden)
+
+	restored, err := svc.RestoreEventVersion(t.Context(), "u1", "e1", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 6, restored.Version)
+	assert.Equal(t, "restore version 2", restored.Reason)
+
+	event, err := svc.GetEvent(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Draft", event.Title)
+	_, err = svc.GetAvailability(t.Context(), "e1", "u2")
+	assert.NoError(t, err)
+	_, err = svc.GetAvailability(t.Context(), "e1", "u3")
+	assert.Error(t, err)
+
+	diff, err := svc.DiffEventVersions(t.Context(), "e1", 2, 6)
+	require.NoError(t, err)
+	assert.Empty(t, diff.EventChanges)
+	assert.Empty(t, diff.AvailabilityChanges)
+}
+
+func TestRestoreEventVersion_KeepsGuests(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{
+		ID: "e1", Title: "Draft", DurationMin: 60, Participants: participants(1),
+		Slots: []model.Slot{{Start: at(9, 0), End: at(12, 0)}},
+	}))
+	link, err := svc.CreateMagicLink(t.Context(), "u1", "e1", model.MagicLinkRequest{Name: "Visitor"})
+	require.NoError(t, err)
+
+	_, err = svc.RestoreEventVersion(t.Context(), "u1", "e1", 1)
+	require.NoError(t, err)
+
+	event, err := svc.GetEvent(t.Context(), "e1")
+	require.NoError(t, err)
+	require.Len(t, event.Guests, 1)
+	assert.Equal(t, link.GuestID, event.Guests[0].ID)
+	guest, err := svc.AuthenticateGuest(t.Context(), link.Token)
+	require.NoError(t, err)
+	_, err = svc.SubmitGuestAvailability(t.Context(), guest, []model.Slot{{Start: at(9, 0), End: at(10, 0)}})
+	assert.NoError(t, err)
+}
+
+func TestRestoreEventVersion_Trashed(t *testing.T) {
+	svc := newInMemoryService(t, 1)
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", DurationMin: 30, Participants: participants(1)}))
+	require.NoError(t, svc.DeleteEvent(t.Context(), "u1", "e1"))
+
+	_, err := svc.RestoreEventVersion(t.Context(), "u1", "e1", 2)
+	assert.EqualError(t, err, "version 2 records the deletion of event e1 and cannot be restored")
+	_, err = svc.RestoreEventVersion(t.Context(), "u1", "e1", 1)
+	assert.EqualError(t, err, "event e1 is in the trash and must be restored first")
+
+	_, err = svc.UndeleteEvent(t.Context(), "u1", "e1")
+	require.NoError(t, err)
+	_, err = svc.RestoreEventVersion(t.Context(), "u1", "e1", 1)
+	assert.NoError(t, err)
+}
+
+// brokenVersions fails to append once broken is set.
+type brokenVersions struct {
+	repository.VersionRepository
+	broken bool
+}
+
+func (v *brokenVersions) Append(ctx context.Context, version *model.EventVersion) error {
+	if v.broken {
+		return errors.New("disk full")
+	}
+	return v.VersionRepository.Append(ctx, version)
+}
+
+func TestRestoreEventVersion_RollsBackOnFailure(t *testing.T) {
+	versions := &brokenVersions{VersionRepository: repository.NewInMemoryVersionRepository()}
+	svc := newInMemoryService(t, 3, service.WithVersions(versions))
+	slots := []model.Slot{{Start: at(9, 0), End: at(10, 0)}}
+	require.NoError(t, svc.CreateEvent(t.Context(), "u1", &model.Event{ID: "e1", Title: "Draft", DurationMin: 30, Participants: participants(3)}))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u2", &model.Availability{EventID: "e1", Slots: slots}))
+	require.NoError(t, svc.UpdateEvent(t.Context(), "u1", &model.Event{ID: "e1", Title: "Final", DurationMin: 30, Participants: participants(3)}))
+	require.NoError(t, svc.DeleteAvailability(t.Context(), "u2", "e1", "u2"))
+	require.NoError(t, svc.AddAvailability(t.Context(), "u3", &model.Availability{EventID: "e1", Slots: slots}))
+
+	history, err := svc.GetEventHistory(t.Context(), "e1")
+	require.NoError(t, err)
+	recorded, err := svc.ListEventVersions(t.Context(), "e1")
+	require.NoError(t, err)
+
+	// The event and availability are restored before the new version is
+	// recorded; when that fails they must be put back as they were, and
+	// nothing the restore recorded may be kept.
+	versions.broken = true
+	_, err = svc.RestoreEventVersion(t.Context(), "u1", "e1", 2)
+	assert.ErrorContains(t, err, "disk full")
+	versions.broken = false
+
+	after, err := svc.GetEventHistory(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Equal(t, history, after)
+	afterVersions, err := svc.ListEventVersions(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Equal(t, recorded, afterVersions)
+
+	event, err := svc.GetEvent(t.Context(), "e1")
+	require.NoError(t, err)
+	assert.Equal(t, "Final", event.Title)
+	_, err = svc.GetAvailability(t.Context(), "e1", "u2")
+	assert.Error(t, err)
+	_, err = svc.GetAvailability(t.Context(), "e1", "u3")
+	assert.NoError(t, err)
+}
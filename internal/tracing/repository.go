@@ -0,0 +1,413 @@
+package tracing
+
+import (
+	"context"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"time"
+)
+
+// The types below decorate each repository interface, wrapping every call in
+// a span named after the repository and method and tagged with the event it
+// concerns where the arguments identify one.
+
+type userRepository struct {
+	next repository.UserRepository
+}
+
+// TraceUserRepository starts a span around every call to r.
+func TraceUserRepository(r repository.UserRepository) repository.UserRepository {
+	return &userRepository{next: r}
+}
+
+func (r *userRepository) Get(ctx context.Context, id string) (*model.User, error) {
+	ctx, span := Start(ctx, "UserRepository.Get")
+	defer span.End()
+	v, err := r.next.Get(ctx, id)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *userRepository) GetAll(ctx context.Context) (map[string]*model.User, error) {
+	ctx, span := Start(ctx, "UserRepository.GetAll")
+	defer span.End()
+	v, err := r.next.GetAll(ctx)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *userRepository) Create(ctx context.Context, user *model.User) error {
+	ctx, span := Start(ctx, "UserRepository.Create")
+	defer span.End()
+	err := r.next.Create(ctx, user)
+	RecordError(span, err)
+	return err
+}
+
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := Start(ctx, "UserRepository.Delete")
+	defer span.End()
+	err := r.next.Delete(ctx, id)
+	RecordError(span, err)
+	return err
+}
+
+type eventRepository struct {
+	next repository.EventRepository
+}
+
+// TraceEventRepository starts a span around every call to r.
+func TraceEventRepository(r repository.EventRepository) repository.EventRepository {
+	return &eventRepository{next: r}
+}
+
+func (r *eventRepository) Create(ctx context.Context, event *model.Event) error {
+	ctx, span := Start(ctx, "EventRepository.Create", AttrEventID.String(event.ID))
+	defer span.End()
+	err := r.next.Create(ctx, event)
+	RecordError(span, err)
+	return err
+}
+
+func (r *eventRepository) Get(ctx context.Context, id string) (*model.Event, error) {
+	ctx, span := Start(ctx, "EventRepository.Get", AttrEventID.String(id))
+	defer span.End()
+	v, err := r.next.Get(ctx, id)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *eventRepository) Update(ctx context.Context, event *model.Event) error {
+	ctx, span := Start(ctx, "EventRepository.Update", AttrEventID.String(event.ID))
+	defer span.End()
+	err := r.next.Update(ctx, event)
+	RecordError(span, err)
+	return err
+}
+
+func (r *eventRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := Start(ctx, "EventRepository.Delete", AttrEventID.String(id))
+	defer span.End()
+	err := r.next.Delete(ctx, id)
+	RecordError(span, err)
+	return err
+}
+
+func (r *eventRepository) List(ctx context.Context) ([]*model.Event, error) {
+	ctx, span := Start(ctx, "EventRepository.List")
+	defer span.End()
+	v, err := r.next.List(ctx)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *eventRepository) AllEventIds(ctx context.Context) (map[string]struct{}, error) {
+	ctx, span := Start(ctx, "EventRepository.AllEventIds")
+	defer span.End()
+	v, err := r.next.AllEventIds(ctx)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *eventRepository) Trash(ctx context.Context, id string, at time.Time) error {
+	ctx, span := Start(ctx, "EventRepository.Trash", AttrEventID.String(id))
+	defer span.End()
+	err := r.next.Trash(ctx, id, at)
+	RecordError(span, err)
+	return err
+}
+
+func (r *eventRepository) Restore(ctx context.Context, id string) error {
+	ctx, span := Start(ctx, "EventRepository.Restore", AttrEventID.String(id))
+	defer span.End()
+	err := r.next.Restore(ctx, id)
+	RecordError(span, err)
+	return err
+}
+
+func (r *eventRepository) GetTrashed(ctx context.Context, id string) (*model.Event, error) {
+	ctx, span := Start(ctx, "EventRepository.GetTrashed", AttrEventID.String(id))
+	defer span.End()
+	v, err := r.next.GetTrashed(ctx, id)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *eventRepository) ListTrashed(ctx context.Context) ([]*model.Event, error) {
+	ctx, span := Start(ctx, "EventRepository.ListTrashed")
+	defer span.End()
+	v, err := r.next.ListTrashed(ctx)
+	RecordError(span, err)
+	return v, err
+}
+
+type availabilityRepository struct {
+	next repository.AvailabilityRepository
+}
+
+// TraceAvailabilityRepository starts a span around every call to r.
+func TraceAvailabilityRepository(r repository.AvailabilityRepository) repository.AvailabilityRepository {
+	return &availabilityRepository{next: r}
+}
+
+func (r *availabilityRepository) Get(ctx context.Context, eventID, userID string) (model.Availability, error) {
+	ctx, span := Start(ctx, "AvailabilityRepository.Get", AttrEventID.String(eventID))
+	defer span.End()
+	v, err := r.next.Get(ctx, eventID, userID)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *availabilityRepository) Create(ctx context.Context, av model.Availability) error {
+	ctx, span := Start(ctx, "AvailabilityRepository.Create", AttrEventID.String(av.EventID))
+	defer span.End()
+	err := r.next.Create(ctx, av)
+	RecordError(span, err)
+	return err
+}
+
+func (r *availabilityRepository) Update(ctx context.Context, av model.Availability) error {
+	ctx, span := Start(ctx, "AvailabilityRepository.Update", AttrEventID.String(av.EventID))
+	defer span.End()
+	err := r.next.Update(ctx, av)
+	RecordError(span, err)
+	return err
+}
+
+func (r *availabilityRepository) GetByEvent(ctx context.Context, eventID string) (map[string]model.Availability, error) {
+	ctx, span := Start(ctx, "AvailabilityRepository.GetByEvent", AttrEventID.String(eventID))
+	defer span.End()
+	v, err := r.next.GetByEvent(ctx, eventID)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *availabilityRepository) Delete(ctx context.Context, eventID, userID string) error {
+	ctx, span := Start(ctx, "AvailabilityRepository.Delete", AttrEventID.String(eventID))
+	defer span.End()
+	err := r.next.Delete(ctx, eventID, userID)
+	RecordError(span, err)
+	return err
+}
+
+func (r *availabilityRepository) DeleteByEvent(ctx context.Context, eventID string) error {
+	ctx, span := Start(ctx, "AvailabilityRepository.DeleteByEvent", AttrEventID.String(eventID))
+	defer span.End()
+	err := r.next.DeleteByEvent(ctx, eventID)
+	RecordError(span, err)
+	return err
+}
+
+type credentialRepository struct {
+	next repository.CredentialRepository
+}
+
+// TraceCredentialRepository starts a span around every call to r.
+func TraceCredentialRepository(r repository.CredentialRepository) repository.CredentialRepository {
+	return &credentialRepository{next: r}
+}
+
+func (r *credentialRepository) Create(ctx context.Context, cred *model.Credential) error {
+	ctx, span := Start(ctx, "CredentialRepository.Create")
+	defer span.End()
+	err := r.next.Create(ctx, cred)
+	RecordError(span, err)
+	return err
+}
+
+func (r *credentialRepository) GetByKeyHash(ctx context.Context, hash string) (*model.Credential, error) {
+	ctx, span := Start(ctx, "CredentialRepository.GetByKeyHash")
+	defer span.End()
+	v, err := r.next.GetByKeyHash(ctx, hash)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *credentialRepository) ListByUser(ctx context.Context, userID string) ([]*model.Credential, error) {
+	ctx, span := Start(ctx, "CredentialRepository.ListByUser")
+	defer span.End()
+	v, err := r.next.ListByUser(ctx, userID)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *credentialRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := Start(ctx, "CredentialRepository.Delete")
+	defer span.End()
+	err := r.next.Delete(ctx, id)
+	RecordError(span, err)
+	return err
+}
+
+type magicLinkRepository struct {
+	next repository.MagicLinkRepository
+}
+
+// TraceMagicLinkRepository starts a span around every call to r.
+func TraceMagicLinkRepository(r repository.MagicLinkRepository) repository.MagicLinkRepository {
+	return &magicLinkRepository{next: r}
+}
+
+func (r *magicLinkRepository) Create(ctx context.Context, link *model.MagicLink) error {
+	ctx, span := Start(ctx, "MagicLinkRepository.Create", AttrEventID.String(link.EventID))
+	defer span.End()
+	err := r.next.Create(ctx, link)
+	RecordError(span, err)
+	return err
+}
+
+func (r *magicLinkRepository) Get(ctx context.Context, id string) (*model.MagicLink, error) {
+	ctx, span := Start(ctx, "MagicLinkRepository.Get")
+	defer span.End()
+	v, err := r.next.Get(ctx, id)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *magicLinkRepository) Update(ctx context.Context, link *model.MagicLink) error {
+	ctx, span := Start(ctx, "MagicLinkRepository.Update", AttrEventID.String(link.EventID))
+	defer span.End()
+	err := r.next.Update(ctx, link)
+	RecordError(span, err)
+	return err
+}
+
+func (r *magicLinkRepository) ListByEvent(ctx context.Context, eventID string) ([]*model.MagicLink, error) {
+	ctx, span := Start(ctx, "MagicLinkRepository.ListByEvent", AttrEventID.String(eventID))
+	defer span.End()
+	v, err := r.next.ListByEvent(ctx, eventID)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *magicLinkRepository) DeleteByEvent(ctx context.Context, eventID string) error {
+	ctx, span := Start(ctx, "MagicLinkRepository.DeleteByEvent", AttrEventID.String(eventID))
+	defer span.End()
+	err := r.next.DeleteByEvent(ctx, eventID)
+	RecordError(span, err)
+	return err
+}
+
+type auditRepository struct {
+	next repository.AuditRepository
+}
+
+// TraceAuditRepository starts a span around every call to r.
+func TraceAuditRepository(r repository.AuditRepository) repository.AuditRepository {
+	return &auditRepository{next: r}
+}
+
+func (r *auditRepository) Append(ctx context.Context, entry *model.AuditEntry) error {
+	ctx, span := Start(ctx, "AuditRepository.Append", AttrEventID.String(entry.EventID))
+	defer span.End()
+	err := r.next.Append(ctx, entry)
+	RecordError(span, err)
+	return err
+}
+
+func (r *auditRepository) Query(ctx context.Context, filter model.AuditFilter) ([]model.AuditEntry, error) {
+	ctx, span := Start(ctx, "AuditRepository.Query")
+	defer span.End()
+	v, err := r.next.Query(ctx, filter)
+	RecordError(span, err)
+	return v, err
+}
+
+type versionRepository struct {
+	next repository.VersionRepository
+}
+
+// TraceVersionRepository starts a span around every call to r.
+func TraceVersionRepository(r repository.VersionRepository) repository.VersionRepository {
+	return &versionRepository{next: r}
+}
+
+func (r *versionRepository) Append(ctx context.Context, v *model.EventVersion) error {
+	ctx, span := Start(ctx, "VersionRepository.Append", AttrEventID.String(v.EventID))
+	defer span.End()
+	err := r.next.Append(ctx, v)
+	RecordError(span, err)
+	return err
+}
+
+func (r *versionRepository) Get(ctx context.Context, eventID string, version int) (*model.EventVersion, error) {
+	ctx, span := Start(ctx, "VersionRepository.Get", AttrEventID.String(eventID))
+	defer span.End()
+	v, err := r.next.Get(ctx, eventID, version)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *versionRepository) List(ctx context.Context, eventID string) ([]model.EventVersion, error) {
+	ctx, span := Start(ctx, "VersionRepository.List", AttrEventID.String(eventID))
+	defer span.End()
+	v, err := r.next.List(ctx, eventID)
+	RecordError(span, err)
+	return v, err
+}
+
+func (r *versionRepository) DeleteByEvent(ctx context.Context, eventID string) error {
+	ctx, span := Start(ctx, "VersionRepository.DeleteByEvent", AttrEventID.String(eventID))
+	defer span.End()
+	err := r.next.DeleteByEvent(ctx, eventID)
+	RecordError(span, err)
+	return err
+}
+
+// Health checks pass through to the decorated repository.
+
+func (r *userRepository) Ping(ctx context.Context) error {
+	ctx, span := Start(ctx, "UserRepository.Ping")
+	defer span.End()
+	err := repository.Ping(ctx, r.next)
+	RecordError(span, err)
+	return err
+}
+
+func (r *eventRepository) Ping(ctx context.Context) error {
+	ctx, span := Start(ctx, "EventRepository.Ping")
+	defer span.End()
+	err := repository.Ping(ctx, r.next)
+	RecordError(span, err)
+	return err
+}
+
+func (r *availabilityRepository) Ping(ctx context.Context) error {
+	ctx, span := Start(ctx, "AvailabilityRepository.Ping")
+	defer span.End()
+	err := repository.Ping(ctx, r.next)
+	RecordError(span, err)
+	return err
+}
+
+func (r *credentialRepository) Ping(ctx context.Context) error {
+	ctx, span := Start(ctx, "CredentialRepository.Ping")
+	defer span.End()
+	err := repository.Ping(ctx, r.next)
+	RecordError(span, err)
+	return err
+}
+
+func (r *magicLinkRepository) Ping(ctx context.Context) error {
+	ctx, span := Start(ctx, "MagicLinkRepository.Ping")
+	defer span.End()
+	err := repository.Ping(ctx, r.next)
+	RecordError(span, err)
+	return err
+}
+
+func (r *auditRepository) Ping(ctx context.Context) error {
+	ctx, span := Start(ctx, "AuditRepository.Ping")
+	defer span.End()
+	err := repository.Ping(ctx, r.next)
+	RecordError(span, err)
+	return err
+}
+
+func (r *versionRepository) Ping(ctx context.Context) error {
+	ctx, span := Start(ctx, "VersionRepository.Ping")
+	defer span.End()
+	err := repository.Ping(ctx, r.next)
+	RecordError(span, err)
+	return err
+}
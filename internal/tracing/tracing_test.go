@@ -0,0 +1,167 @@
+package tracing_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"meeting-scheduler/internal/handler"
+	"meeting-scheduler/internal/model"
+	"meeting-scheduler/internal/repository"
+	"meeting-scheduler/internal/service"
+	"meeting-scheduler/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// record installs a tracer provider that keeps every finished span in memory.
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func findSpan(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, s := range spans {
+		if s.Name() == name {
+			return s
+		}
+	}
+	require.Failf(t, "span not found", "no span named %q", name)
+	return nil
+}
+
+func attr(s sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestSpansAcrossLayers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := record(t)
+	svc := service.NewSchedulerService(
+		tracing.TraceUserRepository(repository.NewInMemoryUserRepository()),
+		tracing.TraceEventRepository(repository.NewInMemoryEventRepository()),
+		tracing.TraceAvailabilityRepository(repository.NewInMemoryAvailabilityRepository()),
+	)
+	r := gin.New()
+	r.Use(tracing.Middleware())
+	handler.NewHandler(svc).RegisterRoutes(r)
+
+	do := func(method, path, key string, body any) *httptest.ResponseRecorder {
+		var payload io.Reader
+		if body != nil {
+			b, _ := json.Marshal(body)
+			payload = bytes.NewReader(b)
+		}
+		req := httptest.NewRequest(method, path, payload)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/user", "", model.User{ID: "u1", Name: "One"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var registration model.Registration
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &registration))
+	key := registration.Credential.Key
+
+	start := time.Date(2025, time.May, 20, 9, 0, 0, 0, time.UTC)
+	slots := []model.Slot{{Start: start, End: start.Add(2 * time.Hour)}}
+	w = do(http.MethodPost, "/event", key, model.Event{ID: "e1", DurationMin: 60, Participants: []string{"u1"}, Slots: slots})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = do(http.MethodPost, "/event/availability", key, model.Availability{EventID: "e1", Slots: slots})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = do(http.MethodGet, "/event/e1/suggestions", key, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	spans := recorder.Ended()
+	server := findSpan(t, spans, "GET /event/:id/suggestions")
+	suggest := findSpan(t, spans, "SchedulerService.SuggestSlots")
+	score := findSpan(t, spans, "scoreWindows")
+
+	assert.Equal(t, "e1", attr(server, tracing.AttrEventID).AsString())
+	assert.EqualValues(t, http.StatusOK, attr(server, "http.response.status_code").AsInt64())
+
+	assert.Equal(t, server.SpanContext().SpanID(), suggest.Parent().SpanID())
+	assert.Equal(t, "e1", attr(suggest, tracing.AttrEventID).AsString())
+	assert.EqualValues(t, 1, attr(suggest, tracing.AttrParticipants).AsInt64())
+
+	assert.Equal(t, suggest.SpanContext().SpanID(), score.Parent().SpanID())
+	assert.EqualValues(t, 5, attr(score, tracing.AttrWindows).AsInt64())
+
+	var repoSpans int
+	for _, s := range spans {
+		if s.Parent().SpanID() == suggest.SpanContext().SpanID() && s.Name() == "EventRepository.Get" {
+			repoSpans++
+			assert.Equal(t, "e1", attr(s, tracing.AttrEventID).AsString())
+		}
+	}
+	assert.Positive(t, repoSpans, "the event lookup should be traced under the service span")
+}
+
+func TestMiddleware_ContinuesPropagatedTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := record(t)
+	_, err := tracing.Setup(t.Context(), tracing.Config{Exporter: tracing.ExporterNone})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(tracing.Middleware())
+	r.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	span := findSpan(t, recorder.Ended(), "GET /ping")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	path := filepath.Join(t.TempDir(), "spans.json")
+
+	shutdown, err := tracing.Setup(t.Context(), tracing.Config{Exporter: tracing.ExporterFile, File: path, ServiceName: "test"})
+	require.NoError(t, err)
+	_, span := tracing.Start(t.Context(), "work", tracing.AttrEventID.String("e1"))
+	span.End()
+	require.NoError(t, shutdown(t.Context()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"work"`)
+	assert.Contains(t, string(data), `"event.id"`)
+}
+
+func TestSetup_RejectsUnknownExporter(t *testing.T) {
+	_, err := tracing.Setup(t.Context(), tracing.Config{Exporter: "jaeger"})
+	assert.ErrorContains(t, err, "unknown exporter")
+
+	_, err = tracing.Setup(t.Context(), tracing.Config{Exporter: tracing.ExporterFile})
+	assert.ErrorContains(t, err, "file path")
+}
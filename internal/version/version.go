@@ -0,0 +1,28 @@
+// Package version reports which build of the server is running.
+package version
+
+import "runtime/debug"
+
+// Version is stamped at build time with
+//
+//	-ldflags "-X meeting-scheduler/internal/version.Version=v1.2.3"
+var Version string
+
+// String returns Version, or else the VCS revision the Go toolchain recorded
+// in the binary, or "dev".
+func String() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			if s.Key == "vcs.revision" {
+				if len(s.Value) > 12 {
+					return s.Value[:12]
+				}
+				return s.Value
+			}
+		}
+	}
+	return "dev"
+}